@@ -0,0 +1,140 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDuplicateKey is returned when a key is already bound to a different value.
+	ErrDuplicateKey = errors.New("collection: duplicate key")
+	// ErrDuplicateValue is returned when a value is already bound to a different key.
+	ErrDuplicateValue = errors.New("collection: duplicate value")
+)
+
+// BiMap is a one-to-one map that keeps both keys and values unique,
+// so it can be looked up in either direction.
+type BiMap[K, V comparable] struct {
+	forward map[K]V
+	inverse map[V]K
+}
+
+// NewBiMap returns an empty BiMap.
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{forward: make(map[K]V), inverse: make(map[V]K)}
+}
+
+// SliceToBiMap converts the source slice to a BiMap with keys and values generated by keyFunc and valueFunc.
+// Unlike SliceToMap it does not overwrite duplicates: every conflicting element is reported in the returned error.
+func SliceToBiMap[S ~[]T, T any, K, V comparable](source S, keyFunc func(T) K, valueFunc func(T) V) (*BiMap[K, V], error) {
+	var (
+		result = NewBiMap[K, V]()
+		errs   []error
+	)
+
+	for _, item := range source {
+		if err := result.Put(keyFunc(item), valueFunc(item)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Put binds the key and the value. It fails with ErrDuplicateKey or ErrDuplicateValue
+// if either of them is already bound to something else.
+func (m *BiMap[K, V]) Put(key K, value V) error {
+	if v, ok := m.forward[key]; ok {
+		if v == value {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %v is bound to %v, can't bind to %v", ErrDuplicateKey, key, v, value)
+	}
+
+	if k, ok := m.inverse[value]; ok {
+		return fmt.Errorf("%w: %v is bound to %v, can't bind to %v", ErrDuplicateValue, value, k, key)
+	}
+
+	m.forward[key] = value
+	m.inverse[value] = key
+
+	return nil
+}
+
+// ForcePut binds the key and the value, removing any entries that conflict with them.
+func (m *BiMap[K, V]) ForcePut(key K, value V) {
+	m.Delete(key)
+	m.DeleteValue(value)
+
+	m.forward[key] = value
+	m.inverse[value] = key
+}
+
+// Get returns the value bound to the key.
+func (m *BiMap[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = m.forward[key]
+	return
+}
+
+// GetKey returns the key bound to the value.
+func (m *BiMap[K, V]) GetKey(value V) (key K, ok bool) {
+	key, ok = m.inverse[value]
+	return
+}
+
+// ContainsKey reports whether the key is present.
+func (m *BiMap[K, V]) ContainsKey(key K) bool {
+	return MapContains(m.forward, key)
+}
+
+// ContainsValue reports whether the value is present.
+func (m *BiMap[K, V]) ContainsValue(value V) bool {
+	return MapContains(m.inverse, value)
+}
+
+// Delete removes the entry for the key.
+func (m *BiMap[K, V]) Delete(key K) {
+	if value, ok := m.forward[key]; ok {
+		delete(m.forward, key)
+		delete(m.inverse, value)
+	}
+}
+
+// DeleteValue removes the entry for the value.
+func (m *BiMap[K, V]) DeleteValue(value V) {
+	if key, ok := m.inverse[value]; ok {
+		delete(m.inverse, value)
+		delete(m.forward, key)
+	}
+}
+
+// Len returns the number of entries.
+func (m *BiMap[K, V]) Len() int {
+	return len(m.forward)
+}
+
+// Keys returns all keys in unspecified order.
+func (m *BiMap[K, V]) Keys() []K {
+	return MapKeys(m.forward)
+}
+
+// Values returns all values in unspecified order.
+func (m *BiMap[K, V]) Values() []V {
+	return MapKeys(m.inverse)
+}
+
+// ToMap returns a copy of the key to value mapping.
+func (m *BiMap[K, V]) ToMap() map[K]V {
+	return MapClone(m.forward)
+}
+
+// Inverse returns a value to key view of the BiMap. The view shares storage with m,
+// so changes made through either of them are visible in both.
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{forward: m.inverse, inverse: m.forward}
+}
@@ -0,0 +1,101 @@
+package collection_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestBiMapPut(t *testing.T) {
+	m := collection.NewBiMap[int, string]()
+
+	if err := m.Put(1, "alice"); err != nil {
+		t.Fatalf("Put(1, alice) error = %v", err)
+	}
+
+	if err := m.Put(1, "alice"); err != nil {
+		t.Errorf("Put(1, alice) again error = %v; want nil", err)
+	}
+
+	if err := m.Put(1, "bob"); !errors.Is(err, collection.ErrDuplicateKey) {
+		t.Errorf("Put(1, bob) error = %v; want %v", err, collection.ErrDuplicateKey)
+	}
+
+	if err := m.Put(2, "alice"); !errors.Is(err, collection.ErrDuplicateValue) {
+		t.Errorf("Put(2, alice) error = %v; want %v", err, collection.ErrDuplicateValue)
+	}
+
+	if got := m.ToMap(); !reflect.DeepEqual(got, map[int]string{1: "alice"}) {
+		t.Errorf("rejected Put changed map: %v", got)
+	}
+}
+
+func TestBiMapForcePut(t *testing.T) {
+	m := collection.NewBiMap[int, string]()
+	m.Put(1, "alice")
+	m.Put(2, "bob")
+
+	m.ForcePut(1, "bob")
+
+	if got := m.ToMap(); !reflect.DeepEqual(got, map[int]string{1: "bob"}) {
+		t.Errorf("ForcePut(1, bob) = %v; want map[1:bob]", got)
+	}
+
+	if key, ok := m.GetKey("bob"); !ok || key != 1 {
+		t.Errorf("GetKey(bob) = (%v, %v); want (1, true)", key, ok)
+	}
+
+	if m.ContainsValue("alice") {
+		t.Errorf("ContainsValue(alice) = true; want false")
+	}
+}
+
+func TestBiMapInverse(t *testing.T) {
+	m := collection.NewBiMap[int, string]()
+	m.Put(1, "alice")
+
+	inverse := m.Inverse()
+	if key, ok := inverse.Get("alice"); !ok || key != 1 {
+		t.Errorf("Inverse().Get(alice) = (%v, %v); want (1, true)", key, ok)
+	}
+
+	inverse.Put("bob", 2)
+	if value, ok := m.Get(2); !ok || value != "bob" {
+		t.Errorf("Get(2) after Inverse().Put = (%v, %v); want (bob, true)", value, ok)
+	}
+
+	m.Delete(1)
+	if inverse.ContainsKey("alice") || inverse.Len() != 1 {
+		t.Errorf("Inverse() view is out of sync: %v", inverse.ToMap())
+	}
+}
+
+func TestSliceToBiMap(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	m, err := collection.SliceToBiMap([]user{{1, "alice"}, {2, "bob"}},
+		func(u user) int { return u.ID },
+		func(u user) string { return u.Name },
+	)
+	if err != nil {
+		t.Fatalf("SliceToBiMap() error = %v", err)
+	}
+
+	if id, _ := m.GetKey("bob"); id != 2 {
+		t.Errorf("GetKey(bob) = %v; want 2", id)
+	}
+
+	_, err = collection.SliceToBiMap([]user{{1, "alice"}, {1, "bob"}, {2, "alice"}},
+		func(u user) int { return u.ID },
+		func(u user) string { return u.Name },
+	)
+
+	if !errors.Is(err, collection.ErrDuplicateKey) || !errors.Is(err, collection.ErrDuplicateValue) {
+		t.Errorf("SliceToBiMap() error = %v; want both conflicts", err)
+	}
+}
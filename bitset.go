@@ -0,0 +1,290 @@
+package collection
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// BitSet is a dense set of non-negative integers backed by a slice of words.
+// The zero value is an empty set ready to use.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet returns a BitSet holding the given bits.
+func NewBitSet(bits ...uint) *BitSet {
+	var result = &BitSet{}
+	for _, i := range bits {
+		result.Set(i)
+	}
+
+	return result
+}
+
+// Set sets the bit i.
+func (b *BitSet) Set(i uint) {
+	b.grow(i)
+	b.words[i/wordSize] |= 1 << (i % wordSize)
+}
+
+// Clear clears the bit i.
+func (b *BitSet) Clear(i uint) {
+	if w := i / wordSize; w < uint(len(b.words)) {
+		b.words[w] &^= 1 << (i % wordSize)
+	}
+}
+
+// Flip toggles the bit i.
+func (b *BitSet) Flip(i uint) {
+	b.grow(i)
+	b.words[i/wordSize] ^= 1 << (i % wordSize)
+}
+
+// Test reports whether the bit i is set.
+func (b *BitSet) Test(i uint) bool {
+	var w = i / wordSize
+	return w < uint(len(b.words)) && b.words[w]&(1<<(i%wordSize)) != 0
+}
+
+// SetRange sets the bits in [from, to).
+func (b *BitSet) SetRange(from, to uint) {
+	if from >= to {
+		return
+	}
+
+	b.grow(to - 1)
+	b.applyRange(from, to, func(word, mask uint64) uint64 { return word | mask })
+}
+
+// ClearRange clears the bits in [from, to).
+func (b *BitSet) ClearRange(from, to uint) {
+	var limit = uint(len(b.words)) * wordSize
+	if to > limit {
+		to = limit
+	}
+
+	if from >= to {
+		return
+	}
+
+	b.applyRange(from, to, func(word, mask uint64) uint64 { return word &^ mask })
+}
+
+// FlipRange toggles the bits in [from, to).
+func (b *BitSet) FlipRange(from, to uint) {
+	if from >= to {
+		return
+	}
+
+	b.grow(to - 1)
+	b.applyRange(from, to, func(word, mask uint64) uint64 { return word ^ mask })
+}
+
+func (b *BitSet) applyRange(from, to uint, op func(word, mask uint64) uint64) {
+	var first, last = from / wordSize, (to - 1) / wordSize
+	for w := first; w <= last; w++ {
+		var mask = ^uint64(0)
+		if w == first {
+			mask &= ^uint64(0) << (from % wordSize)
+		}
+
+		if w == last {
+			mask &= ^uint64(0) >> (wordSize - 1 - (to-1)%wordSize)
+		}
+
+		b.words[w] = op(b.words[w], mask)
+	}
+}
+
+// Cardinality returns the number of set bits.
+func (b *BitSet) Cardinality() int {
+	var count int
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+
+	return count
+}
+
+// Len returns the number of bits the set can hold without growing.
+func (b *BitSet) Len() uint {
+	return uint(len(b.words)) * wordSize
+}
+
+// NextSet returns the first set bit at or after i.
+// The ok result is false if there is no such bit.
+func (b *BitSet) NextSet(i uint) (next uint, ok bool) {
+	var w = i / wordSize
+	if w >= uint(len(b.words)) {
+		return 0, false
+	}
+
+	var word = b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+
+	for w++; w < uint(len(b.words)); w++ {
+		if b.words[w] != 0 {
+			return w*wordSize + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+
+	return 0, false
+}
+
+// NextClear returns the first clear bit at or after i.
+func (b *BitSet) NextClear(i uint) uint {
+	var w = i / wordSize
+	if w >= uint(len(b.words)) {
+		return i
+	}
+
+	var word = ^b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word))
+	}
+
+	for w++; w < uint(len(b.words)); w++ {
+		if b.words[w] != ^uint64(0) {
+			return w*wordSize + uint(bits.TrailingZeros64(^b.words[w]))
+		}
+	}
+
+	return uint(len(b.words)) * wordSize
+}
+
+// Each calls do for each set bit in ascending order. If do returns false, the iteration stops.
+func (b *BitSet) Each(do func(i uint) bool) {
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		if !do(i) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the set bits in ascending order.
+func (b *BitSet) ToSlice() []uint {
+	var result = make([]uint, 0, b.Cardinality())
+	b.Each(func(i uint) bool {
+		result = append(result, i)
+		return true
+	})
+
+	return result
+}
+
+// Clone returns a copy of the set.
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: Clone(b.words)}
+}
+
+// Equal reports whether both sets hold the same bits.
+func (b *BitSet) Equal(other *BitSet) bool {
+	return Equal(b.trimmed(), other.trimmed())
+}
+
+// And returns a new set with the bits set in both b and other.
+func (b *BitSet) And(other *BitSet) *BitSet {
+	return b.combine(other, func(l, r uint64) uint64 { return l & r })
+}
+
+// Or returns a new set with the bits set in b or other.
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	return b.combine(other, func(l, r uint64) uint64 { return l | r })
+}
+
+// Xor returns a new set with the bits set in exactly one of b and other.
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	return b.combine(other, func(l, r uint64) uint64 { return l ^ r })
+}
+
+// AndNot returns a new set with the bits set in b but not in other.
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	return b.combine(other, func(l, r uint64) uint64 { return l &^ r })
+}
+
+func (b *BitSet) combine(other *BitSet, op func(l, r uint64) uint64) *BitSet {
+	var result = &BitSet{words: make([]uint64, Max(len(b.words), len(other.words)))}
+	for i := range result.words {
+		var l, r uint64
+		if i < len(b.words) {
+			l = b.words[i]
+		}
+
+		if i < len(other.words) {
+			r = other.words[i]
+		}
+
+		result.words[i] = op(l, r)
+	}
+
+	result.words = result.trimmed()
+
+	return result
+}
+
+func (b *BitSet) grow(i uint) {
+	var need = int(i/wordSize) + 1
+	if need > len(b.words) {
+		b.words = append(b.words, make([]uint64, need-len(b.words))...)
+	}
+}
+
+func (b *BitSet) trimmed() []uint64 {
+	var n = len(b.words)
+	for n > 0 && b.words[n-1] == 0 {
+		n--
+	}
+
+	return b.words[:n]
+}
+
+var errBitSetData = errors.New("collection: invalid bitset data")
+
+// MarshalBinary encodes the set as little-endian 64-bit words.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	var words = b.trimmed()
+
+	var data = make([]byte, len(words)*8)
+	for i, w := range words {
+		binary.LittleEndian.PutUint64(data[i*8:], w)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a set encoded by MarshalBinary.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return errBitSetData
+	}
+
+	b.words = make([]uint64, len(data)/8)
+	for i := range b.words {
+		b.words[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the set as an ascending array of set bits.
+func (b *BitSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.ToSlice())
+}
+
+// UnmarshalJSON decodes an array of set bits.
+func (b *BitSet) UnmarshalJSON(data []byte) error {
+	var bits []uint
+	if err := json.Unmarshal(data, &bits); err != nil {
+		return err
+	}
+
+	*b = *NewBitSet(bits...)
+
+	return nil
+}
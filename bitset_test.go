@@ -0,0 +1,265 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestBitSet(t *testing.T) {
+	b := collection.NewBitSet(1, 64, 130)
+	b.Flip(2)
+	b.Flip(130)
+	b.Clear(1)
+	b.Clear(10_000)
+
+	if got := b.ToSlice(); !slices.Equal(got, []uint{2, 64}) {
+		t.Errorf("ToSlice() = %v; want [2 64]", got)
+	}
+
+	if !b.Test(64) || b.Test(63) || b.Test(10_000) {
+		t.Errorf("Test() mismatch for %v", b.ToSlice())
+	}
+
+	if b.Cardinality() != 2 {
+		t.Errorf("Cardinality() = %v; want 2", b.Cardinality())
+	}
+}
+
+func TestBitSetRange(t *testing.T) {
+	cases := []struct {
+		name string
+		do   func(b *collection.BitSet)
+		want []uint
+	}{
+		{name: "set within word", do: func(b *collection.BitSet) { b.SetRange(3, 6) }, want: []uint{3, 4, 5}},
+		{name: "set across words", do: func(b *collection.BitSet) { b.SetRange(62, 66) }, want: []uint{62, 63, 64, 65}},
+		{name: "clear range", do: func(b *collection.BitSet) {
+			b.SetRange(0, 130)
+			b.ClearRange(2, 128)
+			b.ClearRange(129, 1000)
+		}, want: []uint{0, 1, 128}},
+		{name: "flip range", do: func(b *collection.BitSet) {
+			b.Set(64)
+			b.FlipRange(63, 66)
+		}, want: []uint{63, 65}},
+		{name: "empty range", do: func(b *collection.BitSet) { b.SetRange(5, 5) }, want: []uint{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b collection.BitSet
+			tc.do(&b)
+
+			if got := b.ToSlice(); !slices.Equal(got, tc.want) {
+				t.Errorf("ToSlice() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBitSetNext(t *testing.T) {
+	b := collection.NewBitSet(0, 1, 2, 70, 200)
+	b.SetRange(64, 128)
+
+	if next, ok := b.NextSet(3); !ok || next != 64 {
+		t.Errorf("NextSet(3) = (%v, %v); want (64, true)", next, ok)
+	}
+
+	if next, ok := b.NextSet(129); !ok || next != 200 {
+		t.Errorf("NextSet(129) = (%v, %v); want (200, true)", next, ok)
+	}
+
+	if _, ok := b.NextSet(201); ok {
+		t.Errorf("NextSet(201) found a bit")
+	}
+
+	if next := b.NextClear(0); next != 3 {
+		t.Errorf("NextClear(0) = %v; want 3", next)
+	}
+
+	if next := b.NextClear(64); next != 128 {
+		t.Errorf("NextClear(64) = %v; want 128", next)
+	}
+
+	if next := b.NextClear(1000); next != 1000 {
+		t.Errorf("NextClear(1000) = %v; want 1000", next)
+	}
+}
+
+func TestBitSetOperations(t *testing.T) {
+	l := collection.NewBitSet(1, 2, 3, 100)
+	r := collection.NewBitSet(2, 3, 4)
+
+	cases := []struct {
+		name string
+		got  *collection.BitSet
+		want []uint
+	}{
+		{name: "and", got: l.And(r), want: []uint{2, 3}},
+		{name: "or", got: l.Or(r), want: []uint{1, 2, 3, 4, 100}},
+		{name: "xor", got: l.Xor(r), want: []uint{1, 4, 100}},
+		{name: "and not", got: l.AndNot(r), want: []uint{1, 100}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.got.ToSlice(); !slices.Equal(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBitSetEncoding(t *testing.T) {
+	b := collection.NewBitSet(0, 5, 64, 1000)
+	b.Clear(1000)
+
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	var decoded collection.BitSet
+	if err := decoded.UnmarshalBinary(data); err != nil || !decoded.Equal(b) {
+		t.Errorf("binary round trip = %v, %v; want %v", decoded.ToSlice(), err, b.ToSlice())
+	}
+
+	if err := decoded.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Errorf("UnmarshalBinary() accepted truncated data")
+	}
+
+	text, err := json.Marshal(b)
+	if err != nil || string(text) != "[0,5,64]" {
+		t.Errorf("MarshalJSON() = %s, %v; want [0,5,64]", text, err)
+	}
+
+	var fromJSON collection.BitSet
+	if err := json.Unmarshal(text, &fromJSON); err != nil || !fromJSON.Equal(b) {
+		t.Errorf("json round trip = %v, %v; want %v", fromJSON.ToSlice(), err, b.ToSlice())
+	}
+}
+
+func TestRoaringBitmap(t *testing.T) {
+	r := collection.NewRoaringBitmap(7, 1<<20, 1<<31)
+	for i := uint32(0); i < 5000; i++ {
+		r.Add(1<<16 + i*2)
+	}
+
+	if r.Cardinality() != 5003 {
+		t.Errorf("Cardinality() = %v; want 5003", r.Cardinality())
+	}
+
+	if !r.Contains(1<<16+4998) || r.Contains(1<<16+4999) || !r.Contains(1<<31) {
+		t.Errorf("Contains() mismatch")
+	}
+
+	for i := uint32(0); i < 5000; i++ {
+		r.Remove(1<<16 + i*2)
+	}
+
+	r.Remove(12345)
+
+	if got := r.ToSlice(); !slices.Equal(got, []uint32{7, 1 << 20, 1 << 31}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+}
+
+func roaringRange(from, to, step uint32) *collection.RoaringBitmap {
+	var r = collection.NewRoaringBitmap()
+	for i := from; i < to; i += step {
+		r.Add(i)
+	}
+
+	return r
+}
+
+func TestRoaringBitmapOperations(t *testing.T) {
+	var (
+		dense  = roaringRange(0, 10_000, 1)
+		sparse = roaringRange(5_000, 200_000, 5)
+		other  = collection.NewRoaringBitmap(3, 70_000, 1<<30)
+	)
+
+	cases := []struct {
+		name string
+		l, r *collection.RoaringBitmap
+		op   func(l, r *collection.RoaringBitmap) *collection.RoaringBitmap
+		want func(x uint32) bool
+	}{
+		{name: "and dense sparse", l: dense, r: sparse, op: (*collection.RoaringBitmap).And, want: func(x uint32) bool {
+			return dense.Contains(x) && sparse.Contains(x)
+		}},
+		{name: "or dense sparse", l: dense, r: sparse, op: (*collection.RoaringBitmap).Or, want: func(x uint32) bool {
+			return dense.Contains(x) || sparse.Contains(x)
+		}},
+		{name: "xor dense sparse", l: dense, r: sparse, op: (*collection.RoaringBitmap).Xor, want: func(x uint32) bool {
+			return dense.Contains(x) != sparse.Contains(x)
+		}},
+		{name: "and not sparse other", l: sparse, r: other, op: (*collection.RoaringBitmap).AndNot, want: func(x uint32) bool {
+			return sparse.Contains(x) && !other.Contains(x)
+		}},
+		{name: "or sparse other", l: sparse, r: other, op: (*collection.RoaringBitmap).Or, want: func(x uint32) bool {
+			return sparse.Contains(x) || other.Contains(x)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.op(tc.l, tc.r)
+
+			var want []uint32
+			for _, src := range []*collection.RoaringBitmap{tc.l, tc.r} {
+				src.Each(func(x uint32) bool {
+					if tc.want(x) {
+						want = append(want, x)
+					}
+					return true
+				})
+			}
+
+			slices.Sort(want)
+			want = slices.Compact(want)
+
+			if !slices.Equal(got.ToSlice(), want) {
+				t.Errorf("got %v values; want %v", got.Cardinality(), len(want))
+			}
+
+			if !got.Equal(collection.NewRoaringBitmap(want...)) {
+				t.Errorf("result is not equal to a freshly built bitmap")
+			}
+		})
+	}
+}
+
+func TestRoaringBitmapEncoding(t *testing.T) {
+	r := roaringRange(0, 10_000, 1).Or(collection.NewRoaringBitmap(1<<20, 1<<31))
+
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	var decoded collection.RoaringBitmap
+	if err := decoded.UnmarshalBinary(data); err != nil || !decoded.Equal(r) {
+		t.Errorf("binary round trip failed: %v", err)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("UnmarshalBinary() accepted truncated data")
+	}
+
+	small := collection.NewRoaringBitmap(1, 1<<20)
+
+	text, err := json.Marshal(small)
+	if err != nil || string(text) != "[1,1048576]" {
+		t.Errorf("MarshalJSON() = %s, %v", text, err)
+	}
+
+	var fromJSON collection.RoaringBitmap
+	if err := json.Unmarshal(text, &fromJSON); err != nil || !fromJSON.Equal(small) {
+		t.Errorf("json round trip = %v, %v", fromJSON.ToSlice(), err)
+	}
+}
@@ -0,0 +1,219 @@
+package collection
+
+import (
+	"sort"
+	"sync"
+)
+
+// Counter is a multiset that counts occurrences of items.
+// Counts are always positive: items whose count drops to zero are removed.
+type Counter[T comparable] struct {
+	m     map[T]int
+	total int
+}
+
+// NewCounter returns a Counter holding the given items.
+func NewCounter[T comparable](items ...T) *Counter[T] {
+	var result = &Counter[T]{m: make(map[T]int, len(items))}
+	for _, item := range items {
+		result.Add(item)
+	}
+
+	return result
+}
+
+// CountBy counts the elements of the slice by a key returned by the given key function.
+func CountBy[S ~[]T, T any, K comparable](source S, keyFunc func(T) K) *Counter[K] {
+	var result = NewCounter[K]()
+	for _, v := range source {
+		result.Add(keyFunc(v))
+	}
+
+	return result
+}
+
+// Add increments the count of the item by one.
+func (c *Counter[T]) Add(item T) {
+	c.AddN(item, 1)
+}
+
+// AddN increments the count of the item by n. Negative n decrements it.
+func (c *Counter[T]) AddN(item T, n int) {
+	var count = c.m[item] + n
+	if count <= 0 {
+		c.total -= c.m[item]
+		delete(c.m, item)
+		return
+	}
+
+	c.total += n
+	c.m[item] = count
+}
+
+// Remove decrements the count of the item by one.
+func (c *Counter[T]) Remove(item T) {
+	c.AddN(item, -1)
+}
+
+// Count returns the count of the item.
+func (c *Counter[T]) Count(item T) int {
+	return c.m[item]
+}
+
+// Total returns the sum of all counts.
+func (c *Counter[T]) Total() int {
+	return c.total
+}
+
+// Len returns the number of distinct items.
+func (c *Counter[T]) Len() int {
+	return len(c.m)
+}
+
+// Items returns the distinct items in unspecified order.
+func (c *Counter[T]) Items() []T {
+	return MapKeys(c.m)
+}
+
+// Counts returns a copy of the item to count mapping.
+func (c *Counter[T]) Counts() map[T]int {
+	return MapClone(c.m)
+}
+
+// MostCommon returns the n items with the highest counts, ordered from the most common.
+// Negative n returns all items. Items with equal counts are returned in unspecified order.
+func (c *Counter[T]) MostCommon(n int) []KV[T, int] {
+	return c.top(n, func(l, r int) bool { return l > r })
+}
+
+// LeastCommon returns the n items with the lowest counts, ordered from the least common.
+// Negative n returns all items. Items with equal counts are returned in unspecified order.
+func (c *Counter[T]) LeastCommon(n int) []KV[T, int] {
+	return c.top(n, func(l, r int) bool { return l < r })
+}
+
+func (c *Counter[T]) top(n int, less func(l, r int) bool) []KV[T, int] {
+	var result = MapToSlice(c.m, func(item T, count int) KV[T, int] {
+		return KV[T, int]{Key: item, Value: count}
+	})
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return less(result[i].Value, result[j].Value)
+	})
+
+	if n >= 0 && n < len(result) {
+		result = result[:n]
+	}
+
+	return result
+}
+
+// Sum returns a new Counter with the counts of c and other added together.
+func (c *Counter[T]) Sum(other *Counter[T]) *Counter[T] {
+	return c.combine(other, func(l, r int) int { return l + r })
+}
+
+// Subtract returns a new Counter with the counts of other subtracted from c.
+// Only positive counts are kept.
+func (c *Counter[T]) Subtract(other *Counter[T]) *Counter[T] {
+	return c.combine(other, func(l, r int) int { return l - r })
+}
+
+// Intersect returns a new Counter with the minimum of the counts in c and other.
+func (c *Counter[T]) Intersect(other *Counter[T]) *Counter[T] {
+	return c.combine(other, Min[int])
+}
+
+// Union returns a new Counter with the maximum of the counts in c and other.
+func (c *Counter[T]) Union(other *Counter[T]) *Counter[T] {
+	return c.combine(other, Max[int])
+}
+
+func (c *Counter[T]) combine(other *Counter[T], op func(l, r int) int) *Counter[T] {
+	var result = NewCounter[T]()
+	for item, count := range c.m {
+		result.AddN(item, op(count, other.m[item]))
+	}
+
+	for item, count := range other.m {
+		if _, ok := c.m[item]; !ok {
+			result.AddN(item, op(0, count))
+		}
+	}
+
+	return result
+}
+
+// SafeCounter is a Counter safe for concurrent use, e.g. for metrics aggregation.
+type SafeCounter[T comparable] struct {
+	mu sync.RWMutex
+	c  *Counter[T]
+}
+
+// NewSafeCounter returns an empty SafeCounter.
+func NewSafeCounter[T comparable]() *SafeCounter[T] {
+	return &SafeCounter[T]{c: NewCounter[T]()}
+}
+
+func (s *SafeCounter[T]) Add(item T) {
+	s.AddN(item, 1)
+}
+
+func (s *SafeCounter[T]) AddN(item T, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.AddN(item, n)
+}
+
+func (s *SafeCounter[T]) Remove(item T) {
+	s.AddN(item, -1)
+}
+
+func (s *SafeCounter[T]) Count(item T) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Count(item)
+}
+
+func (s *SafeCounter[T]) Total() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Total()
+}
+
+func (s *SafeCounter[T]) MostCommon(n int) []KV[T, int] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.MostCommon(n)
+}
+
+func (s *SafeCounter[T]) LeastCommon(n int) []KV[T, int] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.LeastCommon(n)
+}
+
+// Merge adds the counts of other to the counter.
+func (s *SafeCounter[T]) Merge(other *Counter[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for item, count := range other.m {
+		s.c.AddN(item, count)
+	}
+}
+
+// Snapshot returns a point-in-time copy of the counter.
+func (s *SafeCounter[T]) Snapshot() *Counter[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &Counter[T]{m: MapClone(s.c.m), total: s.c.total}
+}
+
+// Reset removes all counts and returns them as a Counter.
+func (s *SafeCounter[T]) Reset() *Counter[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var previous = s.c
+	s.c = NewCounter[T]()
+	return previous
+}
@@ -0,0 +1,118 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestCounter(t *testing.T) {
+	c := collection.NewCounter(strings.Split("a b a c a b", " ")...)
+
+	if c.Count("a") != 3 || c.Count("b") != 2 || c.Count("z") != 0 {
+		t.Errorf("Counts() = %v", c.Counts())
+	}
+
+	if c.Total() != 6 || c.Len() != 3 {
+		t.Errorf("Total(), Len() = %v, %v; want 6, 3", c.Total(), c.Len())
+	}
+
+	c.Remove("c")
+	c.Remove("c")
+	c.AddN("d", 4)
+	c.AddN("a", -1)
+
+	want := map[string]int{"a": 2, "b": 2, "d": 4}
+	if got := c.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts() = %v; want %v", got, want)
+	}
+
+	if c.Total() != 8 {
+		t.Errorf("Total() = %v; want 8", c.Total())
+	}
+}
+
+func TestCounterMostCommon(t *testing.T) {
+	c := collection.NewCounter(1, 2, 2, 3, 3, 3)
+
+	cases := []struct {
+		name string
+		got  []collection.KV[int, int]
+		want []collection.KV[int, int]
+	}{
+		{name: "most common", got: c.MostCommon(2), want: []collection.KV[int, int]{{Key: 3, Value: 3}, {Key: 2, Value: 2}}},
+		{name: "least common", got: c.LeastCommon(1), want: []collection.KV[int, int]{{Key: 1, Value: 1}}},
+		{name: "all", got: c.MostCommon(-1), want: []collection.KV[int, int]{{Key: 3, Value: 3}, {Key: 2, Value: 2}, {Key: 1, Value: 1}}},
+		{name: "more than present", got: c.LeastCommon(10), want: []collection.KV[int, int]{{Key: 1, Value: 1}, {Key: 2, Value: 2}, {Key: 3, Value: 3}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("got %v; want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCounterArithmetic(t *testing.T) {
+	l := collection.NewCounter("a", "a", "a", "b")
+	r := collection.NewCounter("a", "b", "b", "c")
+
+	cases := []struct {
+		name string
+		got  *collection.Counter[string]
+		want map[string]int
+	}{
+		{name: "sum", got: l.Sum(r), want: map[string]int{"a": 4, "b": 3, "c": 1}},
+		{name: "subtract", got: l.Subtract(r), want: map[string]int{"a": 2}},
+		{name: "intersect", got: l.Intersect(r), want: map[string]int{"a": 1, "b": 1}},
+		{name: "union", got: l.Union(r), want: map[string]int{"a": 3, "b": 2, "c": 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.got.Counts(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	c := collection.CountBy([]string{"go", "rust", "c", "zig"}, func(s string) int { return len(s) })
+
+	want := map[int]int{1: 1, 2: 1, 3: 1, 4: 1}
+	if got := c.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CountBy() = %v; want %v", got, want)
+	}
+}
+
+func TestSafeCounter(t *testing.T) {
+	c := collection.NewSafeCounter[string]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Add("requests")
+			}
+			c.Merge(collection.NewCounter("errors"))
+		}()
+	}
+
+	wg.Wait()
+
+	if c.Count("requests") != 800 || c.Count("errors") != 8 || c.Total() != 808 {
+		t.Errorf("Snapshot() = %v", c.Snapshot().Counts())
+	}
+
+	if previous := c.Reset(); previous.Total() != 808 || c.Total() != 0 {
+		t.Errorf("Reset() = %v, left %v", previous.Total(), c.Total())
+	}
+}
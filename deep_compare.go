@@ -0,0 +1,326 @@
+package collection
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DeepCompareOptions configures DeepCompare. The zero value compares like reflect.DeepEqual,
+// except that types with an Equal(T) bool method, such as time.Time, are compared with that method.
+type DeepCompareOptions struct {
+	// IgnoreFields lists struct fields to skip, either by name, e.g. "UpdatedAt",
+	// or by their path of field names without indices and map keys, e.g. "Users.Address.City".
+	IgnoreFields []string
+	// IgnoreOrder compares slices and arrays as multisets.
+	IgnoreOrder bool
+	// FloatTolerance is the largest absolute difference of floats considered equal.
+	FloatTolerance float64
+	// NilEqualsEmpty treats nil slices and maps as equal to empty ones.
+	NilEqualsEmpty bool
+}
+
+// DeepDifference is a difference found by DeepCompare at a path such as `Users[3].Address.City`.
+// Left and Right hold the formatted values, "<missing>" denoting absent elements.
+type DeepDifference struct {
+	Path  string
+	Left  string
+	Right string
+}
+
+// String formats the difference as `Path: Left != Right`.
+func (d DeepDifference) String() string {
+	if d.Path == "" {
+		return d.Left + " != " + d.Right
+	}
+
+	return d.Path + ": " + d.Left + " != " + d.Right
+}
+
+const missingValue = "<missing>"
+
+// DeepCompare walks structs, slices, arrays, maps, pointers and interfaces of a and b and returns
+// their differences, addressed by path. Map entries are visited in order of their formatted keys,
+// so the result is deterministic. It returns nil if the values are equal.
+func DeepCompare[T any](a, b T, options DeepCompareOptions) []DeepDifference {
+	var c = deepComparer{options: options, ignored: InFilter(options.IgnoreFields, true), visited: make(map[deepVisit]bool)}
+	c.compare("", "", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+	return c.differences
+}
+
+// DeepEqual reports whether a and b are equal according to DeepCompare.
+func DeepEqual[T any](a, b T, options DeepCompareOptions) bool {
+	return len(DeepCompare(a, b, options)) == 0
+}
+
+type deepComparer struct {
+	options     DeepCompareOptions
+	ignored     Filter[string]
+	visited     map[deepVisit]bool
+	differences []DeepDifference
+}
+
+type deepVisit struct {
+	a, b uintptr
+	t    reflect.Type
+}
+
+func (c *deepComparer) report(path string, a, b reflect.Value) {
+	var left, right = formatDeepValue(a), formatDeepValue(b)
+	if a.IsValid() && b.IsValid() && a.Type() != b.Type() {
+		left, right = fmt.Sprintf("%s (%v)", left, a.Type()), fmt.Sprintf("%s (%v)", right, b.Type())
+	}
+
+	c.differences = append(c.differences, DeepDifference{Path: path, Left: left, Right: right})
+}
+
+// equal reports whether a and b are equal without recording their differences.
+func (c *deepComparer) equal(a, b reflect.Value) bool {
+	var nested = deepComparer{options: c.options, ignored: c.ignored, visited: make(map[deepVisit]bool)}
+	nested.compare("", "", a, b)
+	return len(nested.differences) == 0
+}
+
+// compare records the differences of a and b. The path addresses the values for reports
+// and fieldPath, the path of field names only, is matched against the ignored fields.
+func (c *deepComparer) compare(path, fieldPath string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			c.report(path, a, b)
+		}
+
+		return
+	}
+
+	if a.Type() != b.Type() {
+		c.report(path, a, b)
+		return
+	}
+
+	// Nil pointers are handled before Equal, which may not accept a nil receiver.
+	if (a.Kind() == reflect.Pointer || a.Kind() == reflect.Interface) && (a.IsNil() || b.IsNil()) {
+		if a.IsNil() != b.IsNil() {
+			c.report(path, a, b)
+		}
+
+		return
+	}
+
+	if equal, ok := equalMethod(a, b); ok {
+		if !equal {
+			c.report(path, a, b)
+		}
+
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.Kind() == reflect.Pointer {
+			var visit = deepVisit{a: a.Pointer(), b: b.Pointer(), t: a.Type()}
+			if c.visited[visit] {
+				return
+			}
+
+			c.visited[visit] = true
+		}
+
+		c.compare(path, fieldPath, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			var (
+				name  = a.Type().Field(i).Name
+				field = strings.TrimPrefix(fieldPath+"."+name, ".")
+			)
+
+			if c.ignored(name) || c.ignored(field) {
+				continue
+			}
+
+			c.compare(strings.TrimPrefix(path+"."+name, "."), field, a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Map:
+		if a.IsNil() != b.IsNil() && !(c.options.NilEqualsEmpty && a.Len() == 0 && b.Len() == 0) {
+			c.report(path, a, b)
+			return
+		}
+
+		if a.Kind() == reflect.Map {
+			c.compareMaps(path, fieldPath, a, b)
+			return
+		}
+
+		c.compareSequences(path, fieldPath, a, b)
+	case reflect.Array:
+		c.compareSequences(path, fieldPath, a, b)
+	case reflect.Float32, reflect.Float64:
+		if math.Abs(a.Float()-b.Float()) > c.options.FloatTolerance || math.IsNaN(a.Float()) || math.IsNaN(b.Float()) {
+			c.report(path, a, b)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		if a.Complex() != b.Complex() {
+			c.report(path, a, b)
+		}
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			c.report(path, a, b)
+		}
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			c.report(path, a, b)
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			c.report(path, a, b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			c.report(path, a, b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			c.report(path, a, b)
+		}
+	default:
+		if a.Pointer() != b.Pointer() {
+			c.report(path, a, b)
+		}
+	}
+}
+
+func (c *deepComparer) compareSequences(path, fieldPath string, a, b reflect.Value) {
+	if c.options.IgnoreOrder {
+		c.compareUnordered(path, fieldPath, a, b)
+		return
+	}
+
+	for i := 0; i < Max(a.Len(), b.Len()); i++ {
+		var left, right reflect.Value
+		if i < a.Len() {
+			left = a.Index(i)
+		}
+
+		if i < b.Len() {
+			right = b.Index(i)
+		}
+
+		c.compare(fmt.Sprintf("%s[%d]", path, i), fieldPath, left, right)
+	}
+}
+
+// compareUnordered matches every element of a with an equal, not yet matched element of b.
+// The elements left without a match are compared pairwise in order of their positions.
+func (c *deepComparer) compareUnordered(path, fieldPath string, a, b reflect.Value) {
+	var (
+		matched   = make([]bool, b.Len())
+		unmatched []int
+	)
+
+	for i := 0; i < a.Len(); i++ {
+		var found bool
+		for j := 0; j < b.Len() && !found; j++ {
+			if !matched[j] && c.equal(a.Index(i), b.Index(j)) {
+				matched[j], found = true, true
+			}
+		}
+
+		if !found {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	for j, ok := range matched {
+		if ok {
+			continue
+		}
+
+		if len(unmatched) == 0 {
+			c.compare(fmt.Sprintf("%s[%d]", path, j), fieldPath, reflect.Value{}, b.Index(j))
+			continue
+		}
+
+		c.compare(fmt.Sprintf("%s[%d]", path, unmatched[0]), fieldPath, a.Index(unmatched[0]), b.Index(j))
+		unmatched = unmatched[1:]
+	}
+
+	for _, i := range unmatched {
+		c.compare(fmt.Sprintf("%s[%d]", path, i), fieldPath, a.Index(i), reflect.Value{})
+	}
+}
+
+// compareMaps compares the entries of a and b for the keys of a and the keys only present in b,
+// in order of their formatted form. Keys formatted alike are told apart in paths by their type.
+func (c *deepComparer) compareMaps(path, fieldPath string, a, b reflect.Value) {
+	var (
+		keys  = a.MapKeys()
+		names = make(map[string]int)
+	)
+
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		names[formatDeepValue(key)]++
+	}
+
+	var formatted = TransformBy(keys, func(key reflect.Value) string {
+		var name = formatDeepValue(key)
+		if names[name] > 1 {
+			name = fmt.Sprintf("%s (%v)", name, unwrapInterface(key).Type())
+		}
+
+		return name
+	})
+
+	var order = make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return formatted[order[i]] < formatted[order[j]] })
+
+	for _, i := range order {
+		c.compare(fmt.Sprintf("%s[%s]", path, formatted[i]), fieldPath, a.MapIndex(keys[i]), b.MapIndex(keys[i]))
+	}
+}
+
+// equalMethod compares a and b with the Equal(T) bool method of their type, if it has one.
+func equalMethod(a, b reflect.Value) (equal bool, ok bool) {
+	if !a.CanInterface() || !b.CanInterface() {
+		return false, false
+	}
+
+	var method = a.MethodByName("Equal")
+	if !method.IsValid() {
+		return false, false
+	}
+
+	var t = method.Type()
+	if t.NumIn() != 1 || t.NumOut() != 1 || t.In(0) != a.Type() || t.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+
+	return method.Call([]reflect.Value{b})[0].Bool(), true
+}
+
+func formatDeepValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return missingValue
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return "<nil>"
+		}
+	}
+
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,140 @@
+package collection_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type deepAddress struct {
+	City string
+	Zip  string
+}
+
+type deepUser struct {
+	Name      string
+	Address   *deepAddress
+	Tags      []string
+	Scores    map[string]float64
+	UpdatedAt time.Time
+}
+
+type deepVersion struct {
+	Major int
+}
+
+func (v *deepVersion) Equal(other *deepVersion) bool {
+	return v.Major == other.Major
+}
+
+type deepRelease struct {
+	Version *deepVersion
+}
+
+type deepNode struct {
+	Value int
+	Next  *deepNode
+}
+
+func TestDeepCompare(t *testing.T) {
+	now := time.Now()
+
+	a := map[string][]deepUser{
+		"users": {
+			{Name: "alice", Address: &deepAddress{City: "A", Zip: "1"}, Tags: []string{"x", "y"}, Scores: map[string]float64{"go": 1}, UpdatedAt: now},
+			{Name: "bob", Address: &deepAddress{City: "A"}},
+		},
+	}
+
+	b := map[string][]deepUser{
+		"users": {
+			{Name: "alice", Address: &deepAddress{City: "B", Zip: "1"}, Tags: []string{"y", "x"}, Scores: map[string]float64{"go": 1.0000001, "rust": 2}, UpdatedAt: now.UTC()},
+			{Name: "bob", Address: &deepAddress{City: "B"}, Tags: []string{}},
+			{Name: "carol"},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		options collection.DeepCompareOptions
+		want    []string
+	}{
+		{
+			name: "strict",
+			want: []string{
+				`["users"][0].Address.City: "A" != "B"`,
+				`["users"][0].Tags[0]: "x" != "y"`,
+				`["users"][0].Tags[1]: "y" != "x"`,
+				`["users"][0].Scores["go"]: 1 != 1.0000001`,
+				`["users"][0].Scores["rust"]: <missing> != 2`,
+				`["users"][1].Address.City: "A" != "B"`,
+				`["users"][1].Tags: <nil> != []`,
+				`["users"][2]: <missing> != {carol <nil> [] map[] 0001-01-01 00:00:00 +0000 UTC}`,
+			},
+		},
+		{
+			name: "relaxed",
+			options: collection.DeepCompareOptions{
+				IgnoreFields:   []string{"Address.City", "UpdatedAt"},
+				IgnoreOrder:    true,
+				FloatTolerance: 1e-6,
+				NilEqualsEmpty: true,
+			},
+			want: []string{
+				`["users"][0].Scores["rust"]: <missing> != 2`,
+				`["users"][2]: <missing> != {carol <nil> [] map[] 0001-01-01 00:00:00 +0000 UTC}`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := collection.TransformBy(collection.DeepCompare(a, b, tc.options), collection.DeepDifference.String)
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("DeepCompare() =\n%v\nwant\n%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeepEqual(t *testing.T) {
+	cycle := &deepNode{Value: 1}
+	cycle.Next = cycle
+
+	other := &deepNode{Value: 1}
+	other.Next = other
+
+	if !collection.DeepEqual(cycle, other, collection.DeepCompareOptions{}) {
+		t.Errorf("DeepEqual() of equal cycles = false")
+	}
+
+	if !collection.DeepEqual[any](1, 1, collection.DeepCompareOptions{}) {
+		t.Errorf("DeepEqual(1, 1) = false")
+	}
+
+	diffs := collection.DeepCompare[any](1, int64(1), collection.DeepCompareOptions{})
+	if len(diffs) != 1 || diffs[0].String() != "1 (int) != 1 (int64)" {
+		t.Errorf("DeepCompare(1, int64(1)) = %v", diffs)
+	}
+
+	diffs = collection.DeepCompare(map[any]int{1: 1, int64(1): 2}, map[any]int{1: 1, int64(1): 3}, collection.DeepCompareOptions{})
+	if len(diffs) != 1 || diffs[0].String() != "[1 (int64)]: 2 != 3" {
+		t.Errorf("DeepCompare() of keys formatted alike = %v", diffs)
+	}
+
+	diffs = collection.DeepCompare(deepRelease{}, deepRelease{Version: &deepVersion{Major: 1}}, collection.DeepCompareOptions{})
+	if len(diffs) != 1 || diffs[0].Path != "Version" {
+		t.Errorf("DeepCompare() of a nil value with an Equal method = %v", diffs)
+	}
+
+	if !collection.DeepEqual(deepRelease{}, deepRelease{}, collection.DeepCompareOptions{}) {
+		t.Errorf("DeepEqual() of nil values with an Equal method = false")
+	}
+
+	unordered := collection.DeepCompareOptions{IgnoreOrder: true}
+	if collection.DeepEqual([]int{1, 1, 2}, []int{1, 2, 2}, unordered) {
+		t.Errorf("DeepEqual() ignoring order treats slices as sets")
+	}
+}
@@ -0,0 +1,366 @@
+package collection
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SliceStrategy selects how DeepMerge combines slices present in several layers.
+type SliceStrategy int
+
+const (
+	// SliceReplace replaces the slice of earlier layers.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend appends the elements to the slice of earlier layers.
+	SliceAppend
+	// SliceUnion appends the elements missing from the slice of earlier layers and
+	// deep merges the elements identified by DeepMergeOptions.UnionKey into the existing ones.
+	SliceUnion
+)
+
+// NilHandling selects how DeepMerge treats nil values of later layers.
+type NilHandling int
+
+const (
+	// NilIgnore keeps the value of earlier layers.
+	NilIgnore NilHandling = iota
+	// NilOverride replaces the value of earlier layers with nil.
+	NilOverride
+	// NilDelete removes the map entry, or resets the struct field to its zero value.
+	NilDelete
+)
+
+// DeepMergeOptions configures DeepMerge.
+type DeepMergeOptions struct {
+	// Slices selects how slices are combined.
+	Slices SliceStrategy
+	// UnionKey identifies the elements of slices merged with SliceUnion and must return comparable keys.
+	// If it is nil, elements are identified by their whole value, so only missing elements are appended.
+	UnionKey func(element any) any
+	// Nil selects how nil values of later layers are treated. Zero values of struct fields count as nil.
+	Nil NilHandling
+	// Conflict is called when a later layer replaces a different value that can't be merged,
+	// and returns the value to keep, which must have the type of one of them. If it is nil,
+	// the value of the later layer wins.
+	Conflict func(path string, current, incoming any) any
+}
+
+// DeepMergeReport maps the dotted path of every final leaf value to the index of the layer that supplied it.
+// Map keys containing dots can't be told apart from nested keys.
+type DeepMergeReport map[string]int
+
+// DeepMerge merges the layers in order, later layers taking precedence. Maps and structs are
+// merged recursively; other values, including slices merged with SliceReplace and structs without
+// exported fields or with an Equal method, such as time.Time, are replaced.
+// The layers are not modified, but the result may share unmerged values with them.
+// Paths in the report join map keys and struct field names with dots, e.g. "server.port".
+func DeepMerge[T any](options DeepMergeOptions, layers ...T) (T, DeepMergeReport) {
+	var (
+		merger = deepMerger{options: options, report: &mergeSource{}}
+		merged reflect.Value
+		result T
+		report = make(DeepMergeReport)
+	)
+
+	for i := range layers {
+		merger.layer = i
+		merged, _ = merger.merge("", merger.report, merged, reflect.ValueOf(&layers[i]).Elem(), false)
+	}
+
+	if merged.IsValid() {
+		reflect.ValueOf(&result).Elem().Set(merged)
+	}
+
+	merger.report.flatten("", report)
+
+	return result, report
+}
+
+type deepMerger struct {
+	options DeepMergeOptions
+	report  *mergeSource
+	layer   int
+}
+
+// mergeSource is a node of the report tree, holding the layer that supplied a leaf value
+// or the nodes of the map entries and struct fields below it.
+type mergeSource struct {
+	leaf     bool
+	layer    int
+	children map[string]*mergeSource
+}
+
+// child returns the node of the map key or struct field, adding it if it is missing.
+func (s *mergeSource) child(name string) *mergeSource {
+	if s.children == nil {
+		s.children = make(map[string]*mergeSource)
+	}
+
+	var result, ok = s.children[name]
+	if !ok {
+		result = &mergeSource{}
+		s.children[name] = result
+	}
+
+	return result
+}
+
+// flatten adds the leaves of the tree to the report by their dotted path.
+func (s *mergeSource) flatten(path string, report DeepMergeReport) {
+	if s.leaf {
+		report[path] = s.layer
+	}
+
+	for name, child := range s.children {
+		child.flatten(joinMergePath(path, name), report)
+	}
+}
+
+// merge returns current merged with the incoming value. The deleted result reports whether
+// the value should be removed. Within structs, zero values count as nil.
+func (m *deepMerger) merge(path string, source *mergeSource, current, incoming reflect.Value, inStruct bool) (merged reflect.Value, deleted bool) {
+	current, incoming = unwrapInterface(current), unwrapInterface(incoming)
+
+	if isNilValue(incoming, inStruct) {
+		switch m.options.Nil {
+		case NilOverride:
+			m.supply(source, incoming)
+			return incoming, false
+		case NilDelete:
+			m.forget(source)
+			return reflect.Value{}, true
+		}
+
+		return current, false
+	}
+
+	if isNilValue(current, inStruct) {
+		m.supply(source, incoming)
+		return incoming, false
+	}
+
+	if current.Type() == incoming.Type() {
+		switch current.Kind() {
+		case reflect.Map:
+			return m.mergeMaps(path, source, current, incoming), false
+		case reflect.Struct:
+			if mergeableStruct(current.Type()) {
+				return m.mergeStructs(path, source, current, incoming), false
+			}
+		case reflect.Pointer:
+			if mergeableStruct(current.Elem().Type()) {
+				var result = reflect.New(current.Elem().Type())
+				result.Elem().Set(m.mergeStructs(path, source, current.Elem(), incoming.Elem()))
+				return result, false
+			}
+		case reflect.Slice:
+			if m.options.Slices != SliceReplace {
+				var result = m.mergeSlices(current, incoming)
+				m.supply(source, result)
+				return result, false
+			}
+		}
+	}
+
+	var result = incoming
+	if m.options.Conflict != nil && current.CanInterface() && incoming.CanInterface() && !reflect.DeepEqual(current.Interface(), incoming.Interface()) {
+		if kept := m.options.Conflict(path, current.Interface(), incoming.Interface()); kept != nil {
+			result = reflect.ValueOf(kept)
+		}
+	}
+
+	m.supply(source, result)
+
+	return result, false
+}
+
+func (m *deepMerger) mergeMaps(path string, source *mergeSource, current, incoming reflect.Value) reflect.Value {
+	var result = reflect.MakeMapWithSize(current.Type(), current.Len()+incoming.Len())
+	for iter := current.MapRange(); iter.Next(); {
+		result.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	for iter := incoming.MapRange(); iter.Next(); {
+		var (
+			name            = fmt.Sprint(iter.Key().Interface())
+			merged, deleted = m.merge(joinMergePath(path, name), source.child(name), current.MapIndex(iter.Key()), iter.Value(), false)
+		)
+
+		switch {
+		case deleted:
+			result.SetMapIndex(iter.Key(), reflect.Value{})
+		case merged.IsValid():
+			result.SetMapIndex(iter.Key(), merged)
+		}
+	}
+
+	return result
+}
+
+func (m *deepMerger) mergeStructs(path string, source *mergeSource, current, incoming reflect.Value) reflect.Value {
+	var result = reflect.New(current.Type()).Elem()
+	result.Set(current)
+
+	for i := 0; i < current.NumField(); i++ {
+		if !current.Type().Field(i).IsExported() {
+			continue
+		}
+
+		var (
+			name            = current.Type().Field(i).Name
+			merged, deleted = m.merge(joinMergePath(path, name), source.child(name), current.Field(i), incoming.Field(i), true)
+		)
+
+		switch {
+		case deleted:
+			result.Field(i).Set(reflect.Zero(result.Field(i).Type()))
+		case merged.IsValid():
+			result.Field(i).Set(merged)
+		}
+	}
+
+	return result
+}
+
+func (m *deepMerger) mergeSlices(current, incoming reflect.Value) reflect.Value {
+	var result = reflect.MakeSlice(current.Type(), current.Len(), current.Len()+incoming.Len())
+	reflect.Copy(result, current)
+
+	if m.options.Slices == SliceAppend {
+		return reflect.AppendSlice(result, incoming)
+	}
+
+	if m.options.UnionKey == nil {
+		for i := 0; i < incoming.Len(); i++ {
+			var element = incoming.Index(i)
+			if !Any(sliceValues(result), func(v reflect.Value) bool { return reflect.DeepEqual(v.Interface(), element.Interface()) }) {
+				result = reflect.Append(result, element)
+			}
+		}
+
+		return result
+	}
+
+	// Elements are merged by a separate merger, as the report only tracks the slice as a whole.
+	var (
+		elements = deepMerger{options: m.options, report: &mergeSource{}, layer: m.layer}
+		keys     = make(map[any]int)
+	)
+
+	for i := 0; i < result.Len(); i++ {
+		keys[m.options.UnionKey(result.Index(i).Interface())] = i
+	}
+
+	for i := 0; i < incoming.Len(); i++ {
+		var (
+			element = incoming.Index(i)
+			key     = m.options.UnionKey(element.Interface())
+		)
+
+		if j, ok := keys[key]; ok {
+			if merged, deleted := elements.merge("", elements.report, result.Index(j), element, false); merged.IsValid() && !deleted {
+				result.Index(j).Set(merged)
+			}
+
+			continue
+		}
+
+		keys[key] = result.Len()
+		result = reflect.Append(result, element)
+	}
+
+	return result
+}
+
+// supply records the layer as the source of the value and of all leaves below it.
+func (m *deepMerger) supply(source *mergeSource, value reflect.Value) {
+	m.forget(source)
+	m.record(source, value)
+}
+
+func (m *deepMerger) record(source *mergeSource, value reflect.Value) {
+	value = unwrapInterface(value)
+	if value.IsValid() && value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch {
+	case value.IsValid() && value.Kind() == reflect.Map && value.Len() > 0:
+		for iter := value.MapRange(); iter.Next(); {
+			m.record(source.child(fmt.Sprint(iter.Key().Interface())), iter.Value())
+		}
+	case value.IsValid() && mergeableStruct(value.Type()):
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				m.record(source.child(value.Type().Field(i).Name), value.Field(i))
+			}
+		}
+	default:
+		source.leaf, source.layer = true, m.layer
+	}
+}
+
+// forget removes the value and all leaves below it from the report.
+func (m *deepMerger) forget(source *mergeSource) {
+	source.leaf, source.children = false, nil
+}
+
+// mergeableStruct reports whether t is a struct merged field by field. Structs without exported
+// fields or with an Equal method, such as time.Time, are values replaced as a whole.
+func mergeableStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	if method, ok := t.MethodByName("Equal"); ok && method.Type.NumIn() == 2 && method.Type.In(1) == t {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinMergePath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func unwrapInterface(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func isNilValue(v reflect.Value, zero bool) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return true
+		}
+	}
+
+	return zero && v.IsZero()
+}
+
+func sliceValues(v reflect.Value) []reflect.Value {
+	var result = make([]reflect.Value, v.Len())
+	for i := range result {
+		result[i] = v.Index(i)
+	}
+
+	return result
+}
@@ -0,0 +1,200 @@
+package collection_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestDeepMergeMaps(t *testing.T) {
+	var (
+		defaults = map[string]any{
+			"server": map[string]any{"host": "localhost", "port": 8080},
+			"tags":   []any{"base"},
+			"debug":  false,
+		}
+		file = map[string]any{
+			"server": map[string]any{"port": 9090, "tls": map[string]any{"enabled": true}},
+			"tags":   []any{"file"},
+		}
+		env = map[string]any{
+			"server": map[string]any{"host": "example.com", "tls": nil},
+			"debug":  true,
+		}
+	)
+
+	cases := []struct {
+		name       string
+		options    collection.DeepMergeOptions
+		want       map[string]any
+		wantReport collection.DeepMergeReport
+	}{
+		{
+			name: "defaults",
+			want: map[string]any{
+				"server": map[string]any{"host": "example.com", "port": 9090, "tls": map[string]any{"enabled": true}},
+				"tags":   []any{"file"},
+				"debug":  true,
+			},
+			wantReport: collection.DeepMergeReport{"server.host": 2, "server.port": 1, "server.tls.enabled": 1, "tags": 1, "debug": 2},
+		},
+		{
+			name:    "append and delete",
+			options: collection.DeepMergeOptions{Slices: collection.SliceAppend, Nil: collection.NilDelete},
+			want: map[string]any{
+				"server": map[string]any{"host": "example.com", "port": 9090},
+				"tags":   []any{"base", "file"},
+				"debug":  true,
+			},
+			wantReport: collection.DeepMergeReport{"server.host": 2, "server.port": 1, "tags": 1, "debug": 2},
+		},
+		{
+			name:    "override",
+			options: collection.DeepMergeOptions{Nil: collection.NilOverride},
+			want: map[string]any{
+				"server": map[string]any{"host": "example.com", "port": 9090, "tls": nil},
+				"tags":   []any{"file"},
+				"debug":  true,
+			},
+			wantReport: collection.DeepMergeReport{"server.host": 2, "server.port": 1, "server.tls": 2, "tags": 1, "debug": 2},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, report := collection.DeepMerge(tc.options, defaults, file, env)
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DeepMerge() = %v; want %v", got, tc.want)
+			}
+
+			if !reflect.DeepEqual(report, tc.wantReport) {
+				t.Errorf("DeepMerge() report = %v; want %v", report, tc.wantReport)
+			}
+		})
+	}
+
+	if port := defaults["server"].(map[string]any)["port"]; port != 8080 {
+		t.Errorf("DeepMerge() modified a layer: port = %v", port)
+	}
+}
+
+func TestDeepMergeUnionAndConflicts(t *testing.T) {
+	var (
+		base = map[string]any{"users": []any{
+			map[string]any{"id": 1, "role": "admin"},
+			map[string]any{"id": 2, "role": "dev"},
+		}}
+		patch = map[string]any{"users": []any{
+			map[string]any{"id": 2, "role": "ops"},
+			map[string]any{"id": 3, "role": "dev"},
+		}}
+		conflicts []string
+	)
+
+	options := collection.DeepMergeOptions{
+		Slices:   collection.SliceUnion,
+		UnionKey: func(element any) any { return element.(map[string]any)["id"] },
+		Conflict: func(path string, current, incoming any) any {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v -> %v", path, current, incoming))
+			return current
+		},
+	}
+
+	got, _ := collection.DeepMerge(options, base, patch)
+
+	want := map[string]any{"users": []any{
+		map[string]any{"id": 1, "role": "admin"},
+		map[string]any{"id": 2, "role": "dev"},
+		map[string]any{"id": 3, "role": "dev"},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeepMerge() = %v; want %v", got, want)
+	}
+
+	if want := []string{"role: dev -> ops"}; !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflicts = %v; want %v", conflicts, want)
+	}
+
+	values, _ := collection.DeepMerge(collection.DeepMergeOptions{Slices: collection.SliceUnion}, []int{1, 2}, []int{2, 3})
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("DeepMerge() of slices = %v; want [1 2 3]", values)
+	}
+}
+
+type mergeConfig struct {
+	Name    string
+	Port    int
+	Limits  *mergeLimits
+	Labels  map[string]string
+	private string
+}
+
+type mergeLimits struct {
+	CPU    int
+	Memory int
+}
+
+func TestDeepMergeStructs(t *testing.T) {
+	var (
+		defaults = mergeConfig{Name: "app", Port: 80, Limits: &mergeLimits{CPU: 1, Memory: 256}, Labels: map[string]string{"tier": "web"}, private: "x"}
+		override = mergeConfig{Port: 8080, Limits: &mergeLimits{Memory: 512}, Labels: map[string]string{"env": "prod"}}
+	)
+
+	got, report := collection.DeepMerge(collection.DeepMergeOptions{}, defaults, override)
+
+	want := mergeConfig{Name: "app", Port: 8080, Limits: &mergeLimits{CPU: 1, Memory: 512}, Labels: map[string]string{"tier": "web", "env": "prod"}, private: "x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeepMerge() = %+v; want %+v", got, want)
+	}
+
+	wantReport := collection.DeepMergeReport{"Name": 0, "Port": 1, "Limits.CPU": 0, "Limits.Memory": 1, "Labels.tier": 0, "Labels.env": 1}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("DeepMerge() report = %v; want %v", report, wantReport)
+	}
+
+	if defaults.Limits.Memory != 256 {
+		t.Errorf("DeepMerge() modified a layer")
+	}
+}
+
+func TestDeepMergeTime(t *testing.T) {
+	type schedule struct {
+		Name     string
+		Deadline time.Time
+	}
+
+	var (
+		first  = schedule{Name: "release", Deadline: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+		second = schedule{Deadline: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	)
+
+	got, report := collection.DeepMerge(collection.DeepMergeOptions{}, first, second)
+
+	want := schedule{Name: "release", Deadline: second.Deadline}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeepMerge() = %+v; want %+v", got, want)
+	}
+
+	wantReport := collection.DeepMergeReport{"Name": 0, "Deadline": 1}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("DeepMerge() report = %v; want %v", report, wantReport)
+	}
+}
+
+func TestDeepMergeDottedKeys(t *testing.T) {
+	var (
+		first  = map[string]any{"a": map[string]any{"x": 1}, "a.b": 1}
+		second = map[string]any{"a": 2}
+	)
+
+	_, report := collection.DeepMerge(collection.DeepMergeOptions{}, first, second)
+
+	wantReport := collection.DeepMergeReport{"a": 1, "a.b": 0}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("DeepMerge() report = %v; want %v", report, wantReport)
+	}
+}
@@ -0,0 +1,360 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EditOp is the kind of an edit of an edit script.
+type EditOp int
+
+const (
+	// EditEqual keeps an element.
+	EditEqual EditOp = iota
+	// EditDelete removes an element of the old slice.
+	EditDelete
+	// EditInsert adds an element of the new slice.
+	EditInsert
+	// EditMove moves an element to a different position. It is only produced by DiffByKey.
+	EditMove
+)
+
+// String returns the name of the edit operation.
+func (op EditOp) String() string {
+	switch op {
+	case EditEqual:
+		return "equal"
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	case EditMove:
+		return "move"
+	}
+
+	return fmt.Sprintf("EditOp(%d)", int(op))
+}
+
+// ErrPatchConflict is returned by Patch when the edits don't match the patched slice.
+var ErrPatchConflict = errors.New("collection: patch does not apply")
+
+// Edit is an operation of an edit script turning an old slice into a new one.
+// OldIndex and NewIndex are the positions in the old and new slice the edit applies at:
+// an insert before the old element at OldIndex and a delete before the new element at NewIndex.
+type Edit[T any] struct {
+	Op       EditOp
+	OldIndex int
+	NewIndex int
+	Value    T
+}
+
+// Diff returns the shortest edit script turning a into b, computed with Myers' algorithm
+// in O((N+M)·D) time and linear space, D being the number of inserted and deleted elements.
+func Diff[S ~[]T, T comparable](a, b S) []Edit[T] {
+	return DiffFunc(a, b, func(l, r T) bool { return l == r })
+}
+
+// DiffFunc is like Diff, but compares the elements using the equal function provided.
+func DiffFunc[S ~[]T, T any](a, b S, equal func(l, r T) bool) []Edit[T] {
+	var d = differ[T]{a: a, b: b, equal: equal}
+	d.compare(0, len(a), 0, len(b))
+	return d.edits
+}
+
+type differ[T any] struct {
+	a, b   []T
+	equal  func(l, r T) bool
+	edits  []Edit[T]
+	vf, vb []int
+}
+
+// compare appends the edits turning a[aLo:aHi] into b[bLo:bHi], splitting the problem at the middle snake.
+func (d *differ[T]) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.equal(d.a[aLo], d.b[bLo]) {
+		d.emit(EditEqual, aLo, bLo)
+		aLo, bLo = aLo+1, bLo+1
+	}
+
+	var suffix int
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.equal(d.a[aHi-1-suffix], d.b[bHi-1-suffix]) {
+		suffix++
+	}
+
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			d.emit(EditInsert, aLo, j)
+		}
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			d.emit(EditDelete, i, bLo)
+		}
+	default:
+		var x, y, u, v = d.middleSnake(aLo, aHi, bLo, bHi)
+
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.emit(EditEqual, x, y)
+		}
+
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.emit(EditEqual, aHi+i, bHi+i)
+	}
+}
+
+// middleSnake runs the forward and reverse searches of Myers' algorithm until they overlap
+// and returns the start (x, y) and end (u, v) of the snake where they meet.
+func (d *differ[T]) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	var (
+		n, m   = aHi - aLo, bHi - bLo
+		delta  = n - m
+		odd    = delta%2 != 0
+		limit  = (n + m + 1) / 2
+		offset = limit + 1
+	)
+
+	if size := 2*limit + 3; len(d.vf) < size {
+		d.vf, d.vb = make([]int, size), make([]int, size)
+	}
+
+	d.vf[offset+1], d.vb[offset+1] = 0, 0
+
+	for step := 0; step <= limit; step++ {
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || k != step && d.vf[offset+k-1] < d.vf[offset+k+1] {
+				x = d.vf[offset+k+1]
+			} else {
+				x = d.vf[offset+k-1] + 1
+			}
+
+			var x0, y0 = x, x - k
+			for y := y0; x < n && y < m && d.equal(d.a[aLo+x], d.b[bLo+y]); y++ {
+				x++
+			}
+
+			d.vf[offset+k] = x
+
+			if reverse := delta - k; odd && reverse >= -(step-1) && reverse <= step-1 && x+d.vb[offset+reverse] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + x - k
+			}
+		}
+
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || k != step && d.vb[offset+k-1] < d.vb[offset+k+1] {
+				x = d.vb[offset+k+1]
+			} else {
+				x = d.vb[offset+k-1] + 1
+			}
+
+			var x0, y0 = x, x - k
+			for y := y0; x < n && y < m && d.equal(d.a[aHi-1-x], d.b[bHi-1-y]); y++ {
+				x++
+			}
+
+			d.vb[offset+k] = x
+
+			if forward := delta - k; !odd && forward >= -step && forward <= step && x+d.vf[offset+forward] >= n {
+				return aHi - x, bHi - (x - k), aHi - x0, bHi - y0
+			}
+		}
+	}
+
+	panic("collection: diff found no middle snake")
+}
+
+func (d *differ[T]) emit(op EditOp, i, j int) {
+	var value T
+	switch op {
+	case EditInsert:
+		value = d.b[j]
+	default:
+		value = d.a[i]
+	}
+
+	d.edits = append(d.edits, Edit[T]{Op: op, OldIndex: i, NewIndex: j, Value: value})
+}
+
+// Patch applies the edits to the source and returns the patched slice. Elements between the
+// edits are kept, so scripts without equal edits apply as well. Equal and delete edits must match
+// the elements of the source at their OldIndex, and edits must be in ascending order of OldIndex;
+// otherwise Patch returns an error wrapping ErrPatchConflict.
+func Patch[S ~[]T, T comparable](source S, edits []Edit[T]) (S, error) {
+	var (
+		result = make(S, 0, len(source))
+		next   int
+	)
+
+	for _, edit := range edits {
+		if edit.OldIndex < next || edit.OldIndex > len(source) {
+			return nil, fmt.Errorf("%w: %v at %d out of order", ErrPatchConflict, edit.Op, edit.OldIndex)
+		}
+
+		result = append(result, source[next:edit.OldIndex]...)
+		next = edit.OldIndex
+
+		switch edit.Op {
+		case EditInsert:
+			result = append(result, edit.Value)
+			continue
+		case EditEqual, EditDelete:
+		default:
+			return nil, fmt.Errorf("%w: unsupported %v", ErrPatchConflict, edit.Op)
+		}
+
+		if next == len(source) || source[next] != edit.Value {
+			return nil, fmt.Errorf("%w: %v of %v at %d", ErrPatchConflict, edit.Op, edit.Value, edit.OldIndex)
+		}
+
+		if edit.Op == EditEqual {
+			result = append(result, edit.Value)
+		}
+
+		next++
+	}
+
+	return append(result, source[next:]...), nil
+}
+
+// UnifiedDiff renders the edits in the unified diff format, one element per line formatted with %v,
+// with the given number of unchanged context lines around every hunk. It returns an empty string
+// if the edits contain no changes. Edits other than equal, delete and insert, such as moves, have no
+// unified diff line and are skipped.
+func UnifiedDiff[T any](edits []Edit[T], context int) string {
+	var (
+		builder strings.Builder
+		lo, hi  = -1, -1
+	)
+
+	edits = FilterBy(edits, func(edit Edit[T]) bool {
+		return edit.Op == EditEqual || edit.Op == EditDelete || edit.Op == EditInsert
+	})
+
+	for i, edit := range edits {
+		if edit.Op == EditEqual {
+			continue
+		}
+
+		var from, to = Max(i-context, 0), Min(i+1+context, len(edits))
+		if lo >= 0 && from > hi {
+			writeHunk(&builder, edits[lo:hi])
+			lo = -1
+		}
+
+		if lo < 0 {
+			lo = from
+		}
+
+		hi = to
+	}
+
+	if lo >= 0 {
+		writeHunk(&builder, edits[lo:hi])
+	}
+
+	return builder.String()
+}
+
+func writeHunk[T any](builder *strings.Builder, edits []Edit[T]) {
+	var oldCount, newCount int
+	for _, edit := range edits {
+		if edit.Op != EditInsert {
+			oldCount++
+		}
+
+		if edit.Op != EditDelete {
+			newCount++
+		}
+	}
+
+	// Empty ranges start at the line before them, as in GNU diff.
+	var oldStart, newStart = edits[0].OldIndex, edits[0].NewIndex
+	if oldCount > 0 {
+		oldStart++
+	}
+
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(builder, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, edit := range edits {
+		fmt.Fprintf(builder, "%c%v\n", " -+"[edit.Op], edit.Value)
+	}
+}
+
+// KeyedEdit is an operation of an edit script produced by DiffByKey. OldIndex is -1 for inserts
+// and NewIndex is -1 for deletes; Old and New hold the elements of the old and new slice.
+type KeyedEdit[K comparable, T any] struct {
+	Op       EditOp
+	Key      K
+	OldIndex int
+	NewIndex int
+	Old      T
+	New      T
+}
+
+// DiffByKey compares a and b by the keys of their elements, detecting reordered elements as moves.
+// Elements with keys present in both slices keep their position if they belong to the longest common
+// subsequence of keys and are moved otherwise, so the number of moves is minimal. The result lists
+// the deletes in the order of a, followed by the edits of the elements of b in the order of b.
+// Keys are expected to be unique; duplicate keys are matched in order of occurrence.
+func DiffByKey[S ~[]T, T any, K comparable](a, b S, keyFunc func(T) K) []KeyedEdit[K, T] {
+	var (
+		oldKeys = occurrenceKeys(a, keyFunc)
+		newKeys = occurrenceKeys(b, keyFunc)
+		inOld   = InFilter(oldKeys, true)
+		inNew   = InFilter(newKeys, true)
+		oldPos  = make(map[Pair[K, int]]int, len(a))
+		stay    = make(map[Pair[K, int]]bool)
+		result  []KeyedEdit[K, T]
+	)
+
+	for i, key := range oldKeys {
+		oldPos[key] = i
+	}
+
+	for _, edit := range Diff(FilterBy(oldKeys, inNew), FilterBy(newKeys, inOld)) {
+		if edit.Op == EditEqual {
+			stay[edit.Value] = true
+		}
+	}
+
+	for i, key := range oldKeys {
+		if !inNew(key) {
+			result = append(result, KeyedEdit[K, T]{Op: EditDelete, Key: key.First, OldIndex: i, NewIndex: -1, Old: a[i]})
+		}
+	}
+
+	for j, key := range newKeys {
+		var edit = KeyedEdit[K, T]{Op: EditInsert, Key: key.First, OldIndex: -1, NewIndex: j, New: b[j]}
+		if i, ok := oldPos[key]; ok {
+			edit.Op, edit.OldIndex, edit.Old = EditMove, i, a[i]
+			if stay[key] {
+				edit.Op = EditEqual
+			}
+		}
+
+		result = append(result, edit)
+	}
+
+	return result
+}
+
+// occurrenceKeys pairs the key of every element with the number of earlier elements with the same key.
+func occurrenceKeys[S ~[]T, T any, K comparable](source S, keyFunc func(T) K) []Pair[K, int] {
+	var seen = make(map[K]int, len(source))
+	return TransformBy(source, func(v T) Pair[K, int] {
+		var key = keyFunc(v)
+		seen[key]++
+		return Pair[K, int]{First: key, Second: seen[key] - 1}
+	})
+}
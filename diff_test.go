@@ -0,0 +1,202 @@
+package collection_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func editScript[T any](edits []collection.Edit[T]) string {
+	return strings.Join(collection.TransformBy(edits, func(e collection.Edit[T]) string {
+		return fmt.Sprintf("%c%v", "=-+"[e.Op], e.Value)
+	}), " ")
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "myers paper", a: "ABCABBA", b: "CBABAC", want: "-A +C =B -C =A =B -B =A +C"},
+		{name: "equal", a: "abc", b: "abc", want: "=a =b =c"},
+		{name: "from empty", a: "", b: "ab", want: "+a +b"},
+		{name: "to empty", a: "ab", b: "", want: "-a -b"},
+		{name: "replace", a: "abc", b: "axc", want: "=a -b +x =c"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits := collection.Diff([]byte(tc.a), []byte(tc.b))
+
+			got := editScript(collection.TransformBy(edits, func(e collection.Edit[byte]) collection.Edit[string] {
+				return collection.Edit[string]{Op: e.Op, Value: string(e.Value)}
+			}))
+
+			if got != tc.want {
+				t.Errorf("Diff(%q, %q) = %v; want %v", tc.a, tc.b, got, tc.want)
+			}
+
+			patched, err := collection.Patch([]byte(tc.a), edits)
+			if err != nil || string(patched) != tc.b {
+				t.Errorf("Patch() = %q, %v; want %q", patched, err, tc.b)
+			}
+		})
+	}
+}
+
+func countChanges[T any](edits []collection.Edit[T]) int {
+	return len(collection.FilterBy(edits, func(e collection.Edit[T]) bool { return e.Op != collection.EditEqual }))
+}
+
+func lcsLength(a, b []int) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = collection.Max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	return table[0][0]
+}
+
+func TestDiffIsMinimal(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomSlice := func() []int {
+		result := make([]int, random.Intn(40))
+		for i := range result {
+			result[i] = random.Intn(4)
+		}
+
+		return result
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomSlice(), randomSlice()
+		edits := collection.Diff(a, b)
+
+		if want := len(a) + len(b) - 2*lcsLength(a, b); countChanges(edits) != want {
+			t.Fatalf("Diff(%v, %v) has %d changes; want %d", a, b, countChanges(edits), want)
+		}
+
+		for _, e := range edits {
+			if e.Op != collection.EditInsert && a[e.OldIndex] != e.Value || e.Op != collection.EditDelete && b[e.NewIndex] != e.Value {
+				t.Fatalf("Diff(%v, %v) edit %+v has wrong indices", a, b, e)
+			}
+		}
+
+		if patched, err := collection.Patch(a, edits); err != nil || !slices.Equal(patched, b) {
+			t.Fatalf("Patch(%v) = %v, %v; want %v", a, patched, err, b)
+		}
+	}
+}
+
+func TestPatchWithoutEqualEdits(t *testing.T) {
+	source := []string{"a", "b", "c", "d"}
+	edits := []collection.Edit[string]{
+		{Op: collection.EditDelete, OldIndex: 1, Value: "b"},
+		{Op: collection.EditInsert, OldIndex: 3, Value: "x"},
+	}
+
+	got, err := collection.Patch(source, edits)
+	if err != nil || !slices.Equal(got, []string{"a", "c", "x", "d"}) {
+		t.Errorf("Patch() = %v, %v", got, err)
+	}
+
+	_, err = collection.Patch([]string{"a", "z"}, edits)
+	if !errors.Is(err, collection.ErrPatchConflict) {
+		t.Errorf("Patch() of mismatching source error = %v", err)
+	}
+
+	_, err = collection.Patch(source, []collection.Edit[string]{edits[1], edits[0]})
+	if !errors.Is(err, collection.ErrPatchConflict) {
+		t.Errorf("Patch() of unordered edits error = %v", err)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var (
+		a = strings.Split("1 2 3 4 5 6 7 8 9 10 11 12", " ")
+		b = strings.Split("1 2 x 4 5 6 7 8 9 10 12 13", " ")
+	)
+
+	want := strings.Join([]string{
+		"@@ -1,5 +1,5 @@",
+		" 1",
+		" 2",
+		"-3",
+		"+x",
+		" 4",
+		" 5",
+		"@@ -9,4 +9,4 @@",
+		" 9",
+		" 10",
+		"-11",
+		" 12",
+		"+13",
+		"",
+	}, "\n")
+
+	if got := collection.UnifiedDiff(collection.Diff(a, b), 2); got != want {
+		t.Errorf("UnifiedDiff() =\n%v\nwant\n%v", got, want)
+	}
+
+	if got := collection.UnifiedDiff(collection.Diff(a, a), 2); got != "" {
+		t.Errorf("UnifiedDiff() of equal slices = %q; want empty", got)
+	}
+
+	if got, want := collection.UnifiedDiff(collection.Diff(nil, []string{"a"}), 3), "@@ -0,0 +1,1 @@\n+a\n"; got != want {
+		t.Errorf("UnifiedDiff() from empty = %q; want %q", got, want)
+	}
+	moved := []collection.Edit[int]{{Op: collection.EditMove, Value: 1}, {Op: collection.EditOp(9), Value: 2}, {Op: collection.EditInsert, Value: 3}}
+	if got, want := collection.UnifiedDiff(moved, 1), "@@ -0,0 +1,1 @@\n+3\n"; got != want {
+		t.Errorf("UnifiedDiff() with moves = %q; want %q", got, want)
+	}
+}
+
+func TestDiffByKey(t *testing.T) {
+	type item struct {
+		ID    string
+		Label string
+	}
+
+	var (
+		a = []item{{"a", "A"}, {"b", "B"}, {"c", "C"}, {"d", "D"}}
+		b = []item{{"b", "B"}, {"c", "C2"}, {"a", "A"}, {"e", "E"}}
+	)
+
+	got := collection.TransformBy(collection.DiffByKey(a, b, func(i item) string { return i.ID }), func(e collection.KeyedEdit[string, item]) string {
+		return fmt.Sprintf("%v %s %d->%d", e.Op, e.Key, e.OldIndex, e.NewIndex)
+	})
+
+	want := []string{"delete d 3->-1", "equal b 1->0", "equal c 2->1", "move a 0->2", "insert e -1->3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("DiffByKey() = %v; want %v", got, want)
+	}
+}
+
+// ExampleUnifiedDiff: Example function demonstrating the use of the UnifiedDiff function.
+func ExampleUnifiedDiff() {
+	want := []string{"alice", "bob", "carol"}
+	got := []string{"alice", "bobby", "carol"}
+
+	fmt.Print(collection.UnifiedDiff(collection.Diff(want, got), 1))
+	// Output:
+	// @@ -1,3 +1,3 @@
+	//  alice
+	// -bob
+	// +bobby
+	//  carol
+}
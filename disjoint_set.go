@@ -0,0 +1,175 @@
+package collection
+
+// DisjointSet is a union-find structure that tracks a partition of items into disjoint groups.
+// It uses path compression and union by rank, so operations run in nearly constant amortized time.
+type DisjointSet[T comparable] struct {
+	parent map[T]T
+	rank   map[T]int
+	order  []T
+}
+
+// NewDisjointSet returns a DisjointSet with each of the given items in its own group.
+func NewDisjointSet[T comparable](items ...T) *DisjointSet[T] {
+	var result = &DisjointSet[T]{
+		parent: make(map[T]T, len(items)),
+		rank:   make(map[T]int, len(items)),
+	}
+
+	for _, item := range items {
+		result.Add(item)
+	}
+
+	return result
+}
+
+// Add adds the item as a singleton group if it is not present yet.
+func (d *DisjointSet[T]) Add(item T) {
+	if _, ok := d.parent[item]; ok {
+		return
+	}
+
+	d.parent[item] = item
+	d.order = append(d.order, item)
+}
+
+// Contains reports whether the item was added.
+func (d *DisjointSet[T]) Contains(item T) bool {
+	return MapContains(d.parent, item)
+}
+
+// Find returns the representative item of the item group.
+// An unknown item is added as a singleton group first.
+func (d *DisjointSet[T]) Find(item T) T {
+	d.Add(item)
+
+	var root = item
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+
+	for item != root {
+		item, d.parent[item] = d.parent[item], root
+	}
+
+	return root
+}
+
+// Union merges the groups of a and b and reports whether they were separate.
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	var ra, rb = d.Find(a), d.Find(b)
+	if ra == rb {
+		return false
+	}
+
+	switch {
+	case d.rank[ra] < d.rank[rb]:
+		d.parent[ra] = rb
+	case d.rank[ra] > d.rank[rb]:
+		d.parent[rb] = ra
+	default:
+		d.parent[rb] = ra
+		d.rank[ra]++
+	}
+
+	return true
+}
+
+// Connected reports whether a and b belong to the same group.
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	if !d.Contains(a) || !d.Contains(b) {
+		return a == b
+	}
+
+	return d.Find(a) == d.Find(b)
+}
+
+// Len returns the number of items.
+func (d *DisjointSet[T]) Len() int {
+	return len(d.order)
+}
+
+// Groups returns the groups ordered by their first added item,
+// with items of each group in the order they were added.
+func (d *DisjointSet[T]) Groups() [][]T {
+	var (
+		index  = make(map[T]int)
+		result [][]T
+	)
+
+	for _, item := range d.order {
+		var root = d.Find(item)
+
+		i, ok := index[root]
+		if !ok {
+			i = len(result)
+			index[root] = i
+			result = append(result, nil)
+		}
+
+		result[i] = append(result[i], item)
+	}
+
+	return result
+}
+
+// ClusterBy groups the elements of the slice that are transitively linked by sharing a key.
+// Two elements are linked when any of the key functions returns the same key for both of them.
+// Zero keys, e.g. an empty email, never link elements.
+func ClusterBy[S ~[]T, T any, K comparable](source S, keyFuncs ...func(T) K) []S {
+	var (
+		set  = NewDisjointSet[int]()
+		zero K
+	)
+
+	for i := range source {
+		set.Add(i)
+	}
+
+	for _, keyFunc := range keyFuncs {
+		var seen = make(map[K]int)
+		for i, v := range source {
+			var key = keyFunc(v)
+			if key == zero {
+				continue
+			}
+
+			if j, ok := seen[key]; ok {
+				set.Union(j, i)
+			} else {
+				seen[key] = i
+			}
+		}
+	}
+
+	return clusters(source, set)
+}
+
+// ClusterFunc groups the elements of the slice that are transitively linked by any of the predicates.
+// Every pair of elements is compared, so prefer ClusterBy when links can be expressed as keys.
+func ClusterFunc[S ~[]T, T any](source S, predicates ...func(l, r T) bool) []S {
+	var set = NewDisjointSet[int]()
+
+	for i := range source {
+		set.Add(i)
+	}
+
+	for i := range source {
+		for j := i + 1; j < len(source); j++ {
+			if set.Connected(i, j) {
+				continue
+			}
+
+			if Any(predicates, func(same func(l, r T) bool) bool { return same(source[i], source[j]) }) {
+				set.Union(i, j)
+			}
+		}
+	}
+
+	return clusters(source, set)
+}
+
+func clusters[S ~[]T, T any](source S, set *DisjointSet[int]) []S {
+	return TransformBy(set.Groups(), func(group []int) S {
+		return TransformBy(group, func(i int) T { return source[i] })
+	})
+}
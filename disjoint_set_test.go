@@ -0,0 +1,83 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestDisjointSet(t *testing.T) {
+	d := collection.NewDisjointSet("a", "b", "c", "d", "e")
+
+	if !d.Union("a", "b") || !d.Union("d", "c") || !d.Union("b", "d") {
+		t.Errorf("Union() of separate groups returned false")
+	}
+
+	if d.Union("a", "c") {
+		t.Errorf("Union(a, c) of connected items returned true")
+	}
+
+	if !d.Connected("a", "c") || d.Connected("a", "e") || d.Connected("a", "z") {
+		t.Errorf("Connected() mismatch")
+	}
+
+	if d.Find("c") != d.Find("a") {
+		t.Errorf("Find(c) = %v; want %v", d.Find("c"), d.Find("a"))
+	}
+
+	want := [][]string{{"a", "b", "c", "d"}, {"e"}}
+	if got := d.Groups(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups() = %v; want %v", got, want)
+	}
+
+	if d.Find("f") != "f" || d.Len() != 6 {
+		t.Errorf("Find() of unknown item should add a singleton")
+	}
+}
+
+type customer struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+func TestClusterBy(t *testing.T) {
+	customers := []customer{
+		{Name: "Ann", Email: "ann@example.com"},
+		{Name: "Bob", Phone: "555-0100"},
+		{Name: "Ann S.", Email: "ann@example.com", Phone: "555-0199"},
+		{Name: "Annie", Phone: "555-0199"},
+		{Name: "Carl"},
+		{Name: "Dan"},
+	}
+
+	got := collection.ClusterBy(customers,
+		func(c customer) string { return c.Email },
+		func(c customer) string { return c.Phone },
+	)
+
+	names := collection.TransformBy(got, func(group []customer) []string {
+		return collection.TransformBy(group, func(c customer) string { return c.Name })
+	})
+
+	want := [][]string{{"Ann", "Ann S.", "Annie"}, {"Bob"}, {"Carl"}, {"Dan"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ClusterBy() = %v; want %v", names, want)
+	}
+}
+
+func TestClusterFunc(t *testing.T) {
+	words := []string{"apple", "Apple", "banana", "apricot", "cherry", "BANANA"}
+
+	got := collection.ClusterFunc(words,
+		strings.EqualFold,
+		func(l, r string) bool { return l[:2] == "ap" && r[:2] == "ap" },
+	)
+
+	want := [][]string{{"apple", "Apple", "apricot"}, {"banana", "BANANA"}, {"cherry"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClusterFunc() = %v; want %v", got, want)
+	}
+}
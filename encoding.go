@@ -0,0 +1,191 @@
+package collection
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+)
+
+// ErrSyncMapNotEmpty is returned when decoding into a SyncMap that already holds entries.
+// A sync.Map can't replace its contents atomically, so decoded entries are only stored into an empty one.
+var ErrSyncMapNotEmpty = errors.New("collection: decoding into a non-empty SyncMap")
+
+// MarshalJSON encodes the map as a JSON object. Keys must be strings, integers
+// or implement encoding.TextMarshaler, as required by encoding/json.
+func (s *SafeMap[K, V]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(s.m)
+}
+
+// UnmarshalJSON replaces the map contents with the decoded JSON object.
+func (s *SafeMap[K, V]) UnmarshalJSON(data []byte) error {
+	var m map[K]V
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	s.replace(m)
+	return nil
+}
+
+// GobEncode encodes the map contents using encoding/gob.
+func (s *SafeMap[K, V]) GobEncode() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return gobEncode(s.m)
+}
+
+// GobDecode replaces the map contents with the gob decoded data.
+func (s *SafeMap[K, V]) GobDecode(data []byte) error {
+	m, err := gobDecode[K, V](data)
+	if err != nil {
+		return err
+	}
+
+	s.replace(m)
+	return nil
+}
+
+func (s *SafeMap[K, V]) replace(m map[K]V) {
+	if m == nil {
+		m = make(map[K]V)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = m
+}
+
+// MarshalJSON encodes the map as a JSON object. Keys must be strings, integers
+// or implement encoding.TextMarshaler, as required by encoding/json.
+func (m *SyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.toMap())
+}
+
+// UnmarshalJSON stores the entries of the decoded JSON object in the map.
+// It returns ErrSyncMapNotEmpty if the map already holds entries.
+func (m *SyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded map[K]V
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	return m.load(decoded)
+}
+
+// GobEncode encodes the map contents using encoding/gob.
+func (m *SyncMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncode(m.toMap())
+}
+
+// GobDecode stores the entries of the gob decoded data in the map.
+// It returns ErrSyncMapNotEmpty if the map already holds entries.
+func (m *SyncMap[K, V]) GobDecode(data []byte) error {
+	decoded, err := gobDecode[K, V](data)
+	if err != nil {
+		return err
+	}
+
+	return m.load(decoded)
+}
+
+func (m *SyncMap[K, V]) toMap() map[K]V {
+	var result = make(map[K]V)
+	m.Range(func(key K, value V) bool {
+		result[key] = value
+		return true
+	})
+
+	return result
+}
+
+func (m *SyncMap[K, V]) load(source map[K]V) error {
+	var empty = true
+	m.m.Range(func(_, _ any) bool {
+		empty = false
+		return false
+	})
+
+	if !empty {
+		return ErrSyncMapNotEmpty
+	}
+
+	for key, value := range source {
+		m.m.Store(key, value)
+	}
+
+	return nil
+}
+
+// gobEncode encodes m with encoding/gob. Keys implementing encoding.TextMarshaler
+// are encoded by their text form, since gob itself does not use it.
+func gobEncode[K comparable, V any](m map[K]V) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		encoder = gob.NewEncoder(&buf)
+	)
+
+	if !isTextKey[K]() {
+		if err := encoder.Encode(m); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var text = make(map[string]V, len(m))
+	for k, v := range m {
+		key, err := any(k).(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		text[string(key)] = v
+	}
+
+	if err := encoder.Encode(text); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func gobDecode[K comparable, V any](data []byte) (map[K]V, error) {
+	var decoder = gob.NewDecoder(bytes.NewReader(data))
+
+	if !isTextKey[K]() {
+		var m map[K]V
+		if err := decoder.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		return m, nil
+	}
+
+	var text map[string]V
+	if err := decoder.Decode(&text); err != nil {
+		return nil, err
+	}
+
+	var m = make(map[K]V, len(text))
+	for k, v := range text {
+		var key K
+		if err := any(&key).(encoding.TextUnmarshaler).UnmarshalText([]byte(k)); err != nil {
+			return nil, err
+		}
+
+		m[key] = v
+	}
+
+	return m, nil
+}
+
+func isTextKey[K any]() bool {
+	var key K
+	_, marshaler := any(key).(encoding.TextMarshaler)
+	_, unmarshaler := any(&key).(encoding.TextUnmarshaler)
+	return marshaler && unmarshaler
+}
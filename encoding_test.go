@@ -0,0 +1,174 @@
+package collection_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type point struct {
+	x, y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%d", p.x, p.y)), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &p.x, &p.y)
+	return err
+}
+
+func gobMarshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func gobUnmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func TestSafeMapEncoding(t *testing.T) {
+	stringKeys := collection.NewSafeMap[string, int]()
+	stringKeys.Set("a", 1)
+	stringKeys.Set("b", 2)
+
+	integerKeys := collection.NewSafeMap[int64, string]()
+	integerKeys.Set(-1, "minus one")
+	integerKeys.Set(42, "answer")
+
+	textKeys := collection.NewSafeMap[point, []string]()
+	textKeys.Set(point{1, 2}, []string{"a"})
+	textKeys.Set(point{-3, 4}, []string{"b", "c"})
+
+	testCases := []struct {
+		name      string
+		marshal   func(any) ([]byte, error)
+		unmarshal func([]byte, any) error
+		source    any
+		target    any
+		want      string
+	}{
+		{name: "json string keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: stringKeys, target: &collection.SafeMap[string, int]{}, want: `{"a":1,"b":2}`},
+		{name: "gob string keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: stringKeys, target: &collection.SafeMap[string, int]{}, want: `{"a":1,"b":2}`},
+		{name: "json integer keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: integerKeys, target: &collection.SafeMap[int64, string]{}, want: `{"-1":"minus one","42":"answer"}`},
+		{name: "gob integer keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: integerKeys, target: &collection.SafeMap[int64, string]{}, want: `{"-1":"minus one","42":"answer"}`},
+		{name: "json text marshaler keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: textKeys, target: &collection.SafeMap[point, []string]{}, want: `{"-3:4":["b","c"],"1:2":["a"]}`},
+		{name: "gob text marshaler keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: textKeys, target: &collection.SafeMap[point, []string]{}, want: `{"-3:4":["b","c"],"1:2":["a"]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.marshal(tc.source)
+			if err != nil {
+				t.Fatalf("marshal() error = %v", err)
+			}
+
+			if err := tc.unmarshal(data, tc.target); err != nil {
+				t.Fatalf("unmarshal() error = %v", err)
+			}
+
+			if got, _ := json.Marshal(tc.target); string(got) != tc.want {
+				t.Errorf("round trip = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSyncMapEncoding(t *testing.T) {
+	var stringKeys collection.SyncMap[string, int]
+	stringKeys.Store("a", 1)
+	stringKeys.Store("b", 2)
+
+	var integerKeys collection.SyncMap[uint8, bool]
+	integerKeys.Store(1, true)
+	integerKeys.Store(200, false)
+
+	var textKeys collection.SyncMap[point, float64]
+	textKeys.Store(point{1, 2}, 0.5)
+	textKeys.Store(point{0, 0}, -1)
+
+	testCases := []struct {
+		name      string
+		marshal   func(any) ([]byte, error)
+		unmarshal func([]byte, any) error
+		source    any
+		target    any
+		want      string
+	}{
+		{name: "json string keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: &stringKeys, target: &collection.SyncMap[string, int]{}, want: `{"a":1,"b":2}`},
+		{name: "gob string keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: &stringKeys, target: &collection.SyncMap[string, int]{}, want: `{"a":1,"b":2}`},
+		{name: "json integer keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: &integerKeys, target: &collection.SyncMap[uint8, bool]{}, want: `{"1":true,"200":false}`},
+		{name: "gob integer keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: &integerKeys, target: &collection.SyncMap[uint8, bool]{}, want: `{"1":true,"200":false}`},
+		{name: "json text marshaler keys", marshal: json.Marshal, unmarshal: json.Unmarshal, source: &textKeys, target: &collection.SyncMap[point, float64]{}, want: `{"0:0":-1,"1:2":0.5}`},
+		{name: "gob text marshaler keys", marshal: gobMarshal, unmarshal: gobUnmarshal, source: &textKeys, target: &collection.SyncMap[point, float64]{}, want: `{"0:0":-1,"1:2":0.5}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.marshal(tc.source)
+			if err != nil {
+				t.Fatalf("marshal() error = %v", err)
+			}
+
+			if err := tc.unmarshal(data, tc.target); err != nil {
+				t.Fatalf("unmarshal() error = %v", err)
+			}
+
+			if got, _ := json.Marshal(tc.target); string(got) != tc.want {
+				t.Errorf("round trip = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSafeMapUnmarshalJSONReplaces(t *testing.T) {
+	m := collection.NewSafeMap[string, int]()
+	m.Set("stale", 1)
+
+	if err := json.Unmarshal([]byte(`{"fresh":2}`), m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got := m.Snapshot(); !reflect.DeepEqual(got, map[string]int{"fresh": 2}) {
+		t.Errorf("Unmarshal() = %v; want map[fresh:2]", got)
+	}
+}
+
+func TestSyncMapUnmarshalJSONNotEmpty(t *testing.T) {
+	var m collection.SyncMap[string, int]
+	m.Store("stale", 1)
+
+	if err := json.Unmarshal([]byte(`{"fresh":2}`), &m); !errors.Is(err, collection.ErrSyncMapNotEmpty) {
+		t.Fatalf("Unmarshal() error = %v; want ErrSyncMapNotEmpty", err)
+	}
+
+	if _, ok := m.Load("fresh"); ok {
+		t.Errorf("Unmarshal() stored entries into a non-empty map")
+	}
+}
+
+func TestSafeMapMarshalJSONEmbedded(t *testing.T) {
+	type state struct {
+		Sessions *collection.SafeMap[int, string] `json:"sessions"`
+	}
+
+	s := state{Sessions: collection.NewSafeMap[int, string]()}
+	s.Sessions.Set(7, "alice")
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if want := `{"sessions":{"7":"alice"}}`; string(data) != want {
+		t.Errorf("Marshal() = %s; want %s", data, want)
+	}
+}
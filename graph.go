@@ -0,0 +1,430 @@
+package collection
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Edge is a weighted edge between two nodes.
+type Edge[N comparable] struct {
+	From   N
+	To     N
+	Weight float64
+}
+
+// Graph is a directed or undirected graph with weighted edges.
+// Nodes and edges are kept in insertion order, so traversals are deterministic.
+type Graph[N comparable] struct {
+	directed  bool
+	nodes     []N
+	adjacency map[N][]Edge[N]
+}
+
+// ErrUndirectedGraph is returned by operations that are defined for directed graphs only.
+var ErrUndirectedGraph = errors.New("collection: operation requires a directed graph")
+
+// CycleError is returned by TopologicalSort when the graph has a cycle.
+type CycleError[N comparable] struct {
+	// Path lists the nodes of the cycle, starting and ending with the same node.
+	Path []N
+}
+
+func (e *CycleError[N]) Error() string {
+	return "collection: graph has a cycle: " + strings.Join(TransformBy(e.Path, func(n N) string {
+		return fmt.Sprint(n)
+	}), " -> ")
+}
+
+// NewDirectedGraph returns an empty directed graph.
+func NewDirectedGraph[N comparable]() *Graph[N] {
+	return &Graph[N]{directed: true, adjacency: make(map[N][]Edge[N])}
+}
+
+// NewUndirectedGraph returns an empty undirected graph.
+func NewUndirectedGraph[N comparable]() *Graph[N] {
+	return &Graph[N]{adjacency: make(map[N][]Edge[N])}
+}
+
+// GraphFromAdjacency builds a graph with unit weight edges from an adjacency map,
+// e.g. map[string][]string of service dependencies. The keys of the map are added in ascending order,
+// each followed by its edges, so nodes and edges are in the same order on every run.
+func GraphFromAdjacency[M ~map[N]S, S ~[]N, N constraints.Ordered](adjacency M, directed bool) *Graph[N] {
+	var (
+		g     = newGraph[N](directed)
+		nodes = MapKeys(adjacency)
+	)
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	for _, from := range nodes {
+		g.AddNode(from)
+		for _, to := range adjacency[from] {
+			g.AddEdge(from, to, 1)
+		}
+	}
+
+	return g
+}
+
+// GraphFromEdges builds a graph from a slice of edges.
+func GraphFromEdges[S ~[]Edge[N], N comparable](edges S, directed bool) *Graph[N] {
+	var g = newGraph[N](directed)
+	for _, e := range edges {
+		g.AddEdge(e.From, e.To, e.Weight)
+	}
+
+	return g
+}
+
+func newGraph[N comparable](directed bool) *Graph[N] {
+	if directed {
+		return NewDirectedGraph[N]()
+	}
+
+	return NewUndirectedGraph[N]()
+}
+
+// Directed reports whether the graph is directed.
+func (g *Graph[N]) Directed() bool {
+	return g.directed
+}
+
+// AddNode adds the node if it is not present yet.
+func (g *Graph[N]) AddNode(node N) {
+	if _, ok := g.adjacency[node]; ok {
+		return
+	}
+
+	g.adjacency[node] = nil
+	g.nodes = append(g.nodes, node)
+}
+
+// AddEdge adds a weighted edge, adding missing nodes. In an undirected graph
+// the edge is traversable in both directions.
+func (g *Graph[N]) AddEdge(from, to N, weight float64) {
+	g.AddNode(from)
+	g.AddNode(to)
+
+	g.adjacency[from] = append(g.adjacency[from], Edge[N]{From: from, To: to, Weight: weight})
+	if !g.directed && from != to {
+		g.adjacency[to] = append(g.adjacency[to], Edge[N]{From: to, To: from, Weight: weight})
+	}
+}
+
+// HasNode reports whether the node is present.
+func (g *Graph[N]) HasNode(node N) bool {
+	return MapContains(g.adjacency, node)
+}
+
+// HasEdge reports whether there is an edge from one node to another.
+func (g *Graph[N]) HasEdge(from, to N) bool {
+	return Any(g.adjacency[from], func(e Edge[N]) bool { return e.To == to })
+}
+
+// Nodes returns the nodes in insertion order.
+func (g *Graph[N]) Nodes() []N {
+	return Clone(g.nodes)
+}
+
+// Edges returns the outgoing edges of the node.
+func (g *Graph[N]) Edges(node N) []Edge[N] {
+	return Clone(g.adjacency[node])
+}
+
+// Neighbors returns the nodes reachable from the node by a single edge.
+func (g *Graph[N]) Neighbors(node N) []N {
+	return TransformBy(g.adjacency[node], func(e Edge[N]) N { return e.To })
+}
+
+// BFS visits the nodes reachable from start in breadth-first order.
+// If visit returns false, the traversal stops.
+func (g *Graph[N]) BFS(start N, visit func(node N) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+
+	var (
+		visited = map[N]struct{}{start: {}}
+		queue   = []N{start}
+	)
+
+	for len(queue) > 0 {
+		var node = queue[0]
+		queue = queue[1:]
+
+		if !visit(node) {
+			return
+		}
+
+		for _, e := range g.adjacency[node] {
+			if _, ok := visited[e.To]; !ok {
+				visited[e.To] = struct{}{}
+				queue = append(queue, e.To)
+			}
+		}
+	}
+}
+
+// DFS visits the nodes reachable from start in depth-first preorder.
+// If visit returns false, the traversal stops.
+func (g *Graph[N]) DFS(start N, visit func(node N) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+
+	var (
+		visited = make(map[N]struct{})
+		stack   = []N{start}
+	)
+
+	for len(stack) > 0 {
+		var node = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, ok := visited[node]; ok {
+			continue
+		}
+
+		visited[node] = struct{}{}
+
+		if !visit(node) {
+			return
+		}
+
+		var edges = g.adjacency[node]
+		for i := len(edges) - 1; i >= 0; i-- {
+			if _, ok := visited[edges[i].To]; !ok {
+				stack = append(stack, edges[i].To)
+			}
+		}
+	}
+}
+
+// TopologicalSort orders the nodes so that every edge points from an earlier node to a later one.
+// It returns a *CycleError listing the offending path if the graph has a cycle.
+func (g *Graph[N]) TopologicalSort() ([]N, error) {
+	if !g.directed {
+		return nil, ErrUndirectedGraph
+	}
+
+	var inDegree = make(map[N]int, len(g.nodes))
+	for _, node := range g.nodes {
+		for _, e := range g.adjacency[node] {
+			inDegree[e.To]++
+		}
+	}
+
+	var (
+		queue  = FilterBy(g.nodes, func(node N) bool { return inDegree[node] == 0 })
+		result = make([]N, 0, len(g.nodes))
+	)
+
+	for len(queue) > 0 {
+		var node = queue[0]
+		queue = queue[1:]
+
+		result = append(result, node)
+
+		for _, e := range g.adjacency[node] {
+			inDegree[e.To]--
+			if inDegree[e.To] == 0 {
+				queue = append(queue, e.To)
+			}
+		}
+	}
+
+	if len(result) < len(g.nodes) {
+		return nil, &CycleError[N]{Path: g.findCycle(inDegree)}
+	}
+
+	return result, nil
+}
+
+// findCycle walks backwards over edges between nodes left with positive in-degree after
+// Kahn's algorithm. Each of them has a predecessor among them, so the walk must repeat a node.
+func (g *Graph[N]) findCycle(inDegree map[N]int) []N {
+	var predecessor = make(map[N]N)
+	for _, node := range g.nodes {
+		if inDegree[node] <= 0 {
+			continue
+		}
+
+		for _, e := range g.adjacency[node] {
+			if inDegree[e.To] > 0 {
+				predecessor[e.To] = node
+			}
+		}
+	}
+
+	var (
+		node N
+		seen = make(map[N]int)
+		walk []N
+	)
+
+	for _, n := range g.nodes {
+		if inDegree[n] > 0 {
+			node = n
+			break
+		}
+	}
+
+	for {
+		if i, ok := seen[node]; ok {
+			var cycle = append(walk[i:], node)
+			Reverse(cycle)
+			return cycle
+		}
+
+		seen[node] = len(walk)
+		walk = append(walk, node)
+		node = predecessor[node]
+	}
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the graph
+// using Tarjan's algorithm. For undirected graphs these are the connected components.
+func (g *Graph[N]) StronglyConnectedComponents() [][]N {
+	var (
+		index   = make(map[N]int)
+		lowlink = make(map[N]int)
+		onStack = make(map[N]bool)
+		stack   []N
+		result  [][]N
+		connect func(node N)
+	)
+
+	connect = func(node N) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, e := range g.adjacency[node] {
+			if _, ok := index[e.To]; !ok {
+				connect(e.To)
+				lowlink[node] = Min(lowlink[node], lowlink[e.To])
+			} else if onStack[e.To] {
+				lowlink[node] = Min(lowlink[node], index[e.To])
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+
+		var component []N
+		for {
+			var top = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+
+			if top == node {
+				break
+			}
+		}
+
+		Reverse(component)
+		result = append(result, component)
+	}
+
+	for _, node := range g.nodes {
+		if _, ok := index[node]; !ok {
+			connect(node)
+		}
+	}
+
+	return result
+}
+
+// ShortestPath finds the lowest weight path between two nodes using Dijkstra's algorithm.
+// Edge weights must be non-negative. The ok result is false if to is unreachable.
+func (g *Graph[N]) ShortestPath(from, to N) (path []N, distance float64, ok bool) {
+	return g.AStar(from, to, func(N) float64 { return 0 })
+}
+
+// AStar finds the lowest weight path between two nodes using the A* algorithm.
+// The heuristic estimates the remaining distance to the target and must never overestimate it.
+// It need not be consistent: nodes already expanded are reopened when a shorter path to them is found.
+// Edge weights must be non-negative. The ok result is false if to is unreachable.
+func (g *Graph[N]) AStar(from, to N, heuristic func(node N) float64) (path []N, distance float64, ok bool) {
+	if !g.HasNode(from) || !g.HasNode(to) {
+		return nil, 0, false
+	}
+
+	var (
+		dist     = map[N]float64{from: 0}
+		previous = make(map[N]N)
+		done     = make(map[N]struct{})
+		queue    = &graphQueue[N]{{node: from, priority: heuristic(from)}}
+	)
+
+	for queue.Len() > 0 {
+		var node = heap.Pop(queue).(graphQueueItem[N]).node
+		if _, ok := done[node]; ok {
+			continue
+		}
+
+		if node == to {
+			break
+		}
+
+		done[node] = struct{}{}
+
+		for _, e := range g.adjacency[node] {
+			var candidate = dist[node] + e.Weight
+
+			if current, ok := dist[e.To]; ok && current <= candidate {
+				continue
+			}
+
+			dist[e.To] = candidate
+			previous[e.To] = node
+			delete(done, e.To)
+			heap.Push(queue, graphQueueItem[N]{node: e.To, priority: candidate + heuristic(e.To)})
+		}
+	}
+
+	distance, ok = dist[to]
+	if !ok {
+		return nil, math.Inf(1), false
+	}
+
+	for node := to; node != from; node = previous[node] {
+		path = append(path, node)
+	}
+
+	path = append(path, from)
+	Reverse(path)
+
+	return path, distance, true
+}
+
+type graphQueueItem[N comparable] struct {
+	node     N
+	priority float64
+}
+
+type graphQueue[N comparable] []graphQueueItem[N]
+
+func (q graphQueue[N]) Len() int           { return len(q) }
+func (q graphQueue[N]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q graphQueue[N]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *graphQueue[N]) Push(x any) {
+	*q = append(*q, x.(graphQueueItem[N]))
+}
+
+func (q *graphQueue[N]) Pop() any {
+	var old = *q
+	var item = old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,217 @@
+package collection_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func collectNodes[N comparable](traverse func(start N, visit func(N) bool), start N, limit int) []N {
+	var result []N
+	traverse(start, func(node N) bool {
+		result = append(result, node)
+		return len(result) < limit
+	})
+
+	return result
+}
+
+func TestGraphTraversal(t *testing.T) {
+	g := collection.GraphFromEdges([]collection.Edge[int]{
+		{From: 1, To: 2}, {From: 1, To: 3}, {From: 2, To: 4}, {From: 3, To: 4}, {From: 4, To: 5}, {From: 6, To: 1},
+	}, true)
+
+	cases := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{name: "bfs", got: collectNodes(g.BFS, 1, 10), want: []int{1, 2, 3, 4, 5}},
+		{name: "dfs", got: collectNodes(g.DFS, 1, 10), want: []int{1, 2, 4, 5, 3}},
+		{name: "bfs early exit", got: collectNodes(g.BFS, 1, 2), want: []int{1, 2}},
+		{name: "unknown start", got: collectNodes(g.DFS, 42, 10), want: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !slices.Equal(tc.got, tc.want) {
+				t.Errorf("got %v; want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	deps := map[string][]string{
+		"api":      {"auth", "db"},
+		"auth":     {"db", "cache"},
+		"worker":   {"db"},
+		"db":       nil,
+		"cache":    nil,
+		"frontend": {"api"},
+	}
+
+	g := collection.GraphFromAdjacency(deps, true)
+
+	if want := []string{"api", "auth", "db", "cache", "frontend", "worker"}; !slices.Equal(g.Nodes(), want) {
+		t.Errorf("Nodes() = %v; want %v", g.Nodes(), want)
+	}
+
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort() error = %v", err)
+	}
+
+	position := make(map[string]int)
+	for i, node := range order {
+		position[node] = i
+	}
+
+	if len(order) != len(deps) {
+		t.Fatalf("TopologicalSort() = %v; want all %d nodes", order, len(deps))
+	}
+
+	for from, targets := range deps {
+		for _, to := range targets {
+			if position[from] > position[to] {
+				t.Errorf("TopologicalSort() = %v; %v must precede %v", order, from, to)
+			}
+		}
+	}
+}
+
+func TestGraphTopologicalSortCycle(t *testing.T) {
+	g := collection.NewDirectedGraph[string]()
+	g.AddEdge("root", "a", 1)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("c", "a", 1)
+	g.AddEdge("c", "leaf", 1)
+
+	_, err := g.TopologicalSort()
+
+	var cycle *collection.CycleError[string]
+	if !errors.As(err, &cycle) {
+		t.Fatalf("TopologicalSort() error = %v; want CycleError", err)
+	}
+
+	if want := []string{"a", "b", "c", "a"}; !slices.Equal(cycle.Path, want) {
+		t.Errorf("CycleError.Path = %v; want %v", cycle.Path, want)
+	}
+
+	if want := "collection: graph has a cycle: a -> b -> c -> a"; err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+
+	if _, err := collection.NewUndirectedGraph[int]().TopologicalSort(); !errors.Is(err, collection.ErrUndirectedGraph) {
+		t.Errorf("TopologicalSort() on undirected graph error = %v", err)
+	}
+}
+
+func TestGraphStronglyConnectedComponents(t *testing.T) {
+	g := collection.GraphFromEdges([]collection.Edge[int]{
+		{From: 1, To: 2}, {From: 2, To: 3}, {From: 3, To: 1}, {From: 3, To: 4}, {From: 4, To: 5}, {From: 5, To: 4}, {From: 6, To: 6},
+	}, true)
+	g.AddNode(7)
+
+	got := g.StronglyConnectedComponents()
+	for _, component := range got {
+		slices.Sort(component)
+	}
+
+	want := [][]int{{4, 5}, {1, 2, 3}, {6}, {7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StronglyConnectedComponents() = %v; want %v", got, want)
+	}
+
+	undirected := collection.GraphFromEdges([]collection.Edge[int]{{From: 1, To: 2}, {From: 3, To: 2}, {From: 4, To: 5}}, false)
+	if got := undirected.StronglyConnectedComponents(); len(got) != 2 {
+		t.Errorf("StronglyConnectedComponents() of undirected graph = %v; want 2 components", got)
+	}
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	g := collection.GraphFromEdges([]collection.Edge[string]{
+		{From: "a", To: "b", Weight: 7},
+		{From: "a", To: "c", Weight: 9},
+		{From: "a", To: "f", Weight: 14},
+		{From: "b", To: "c", Weight: 10},
+		{From: "b", To: "d", Weight: 15},
+		{From: "c", To: "d", Weight: 11},
+		{From: "c", To: "f", Weight: 2},
+		{From: "d", To: "e", Weight: 6},
+		{From: "e", To: "f", Weight: 9},
+	}, false)
+	g.AddNode("island")
+
+	path, distance, ok := g.ShortestPath("a", "e")
+	if !ok || distance != 20 || !slices.Equal(path, []string{"a", "c", "f", "e"}) {
+		t.Errorf("ShortestPath(a, e) = %v, %v, %v; want [a c f e], 20, true", path, distance, ok)
+	}
+
+	if path, distance, ok := g.ShortestPath("a", "a"); !ok || distance != 0 || !slices.Equal(path, []string{"a"}) {
+		t.Errorf("ShortestPath(a, a) = %v, %v, %v", path, distance, ok)
+	}
+
+	if _, distance, ok := g.ShortestPath("a", "island"); ok || !math.IsInf(distance, 1) {
+		t.Errorf("ShortestPath(a, island) = %v, %v; want unreachable", distance, ok)
+	}
+}
+
+func TestGraphAStar(t *testing.T) {
+	type cell struct{ x, y int }
+
+	g := collection.NewUndirectedGraph[cell]()
+	wall := map[cell]bool{{1, 0}: true, {1, 1}: true, {1, 2}: true}
+
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			if wall[cell{x, y}] {
+				continue
+			}
+
+			if x+1 < 4 && !wall[cell{x + 1, y}] {
+				g.AddEdge(cell{x, y}, cell{x + 1, y}, 1)
+			}
+
+			if y+1 < 4 && !wall[cell{x, y + 1}] {
+				g.AddEdge(cell{x, y}, cell{x, y + 1}, 1)
+			}
+		}
+	}
+
+	target := cell{3, 0}
+	manhattan := func(c cell) float64 {
+		return math.Abs(float64(c.x-target.x)) + math.Abs(float64(c.y-target.y))
+	}
+
+	path, distance, ok := g.AStar(cell{0, 0}, target, manhattan)
+	if !ok || distance != 9 || len(path) != 10 {
+		t.Errorf("AStar() = %v, %v, %v; want 9 steps around the wall", path, distance, ok)
+	}
+}
+
+func TestGraphAStarInconsistentHeuristic(t *testing.T) {
+	g := collection.GraphFromEdges([]collection.Edge[string]{
+		{From: "s", To: "a", Weight: 1}, {From: "a", To: "c", Weight: 1},
+		{From: "s", To: "c", Weight: 3}, {From: "c", To: "g", Weight: 3},
+	}, true)
+
+	// Admissible, but not consistent: c is expanded through s before the shorter path through a is found.
+	heuristic := func(node string) float64 {
+		if node == "a" {
+			return 4
+		}
+
+		return 0
+	}
+
+	path, distance, ok := g.AStar("s", "g", heuristic)
+	if want := []string{"s", "a", "c", "g"}; !ok || distance != 5 || !slices.Equal(path, want) {
+		t.Errorf("AStar() = %v, %v, %v; want %v, 5", path, distance, ok, want)
+	}
+}
@@ -1,5 +1,7 @@
 package collection
 
+import "golang.org/x/exp/constraints"
+
 // GroupBy groups the elements of the slice by a key returned by the given key function.
 func GroupBy[S ~[]T, T any, K comparable](source S, keyFunc func(T) K) map[K]S {
 	var result = make(map[K]S)
@@ -12,3 +14,224 @@ func GroupBy[S ~[]T, T any, K comparable](source S, keyFunc func(T) K) map[K]S {
 
 	return result
 }
+
+// Aggregator creates the state of a single group. The add function folds an element into the state
+// and the result function returns the aggregated value of the elements added so far.
+type Aggregator[T, R any] func() (add func(T), result func() R)
+
+// FoldAggregator returns an Aggregator folding the elements of a group like Aggregate, starting from the initial value.
+func FoldAggregator[T, R any](initial R, fold func(R, T) R) Aggregator[T, R] {
+	return func() (func(T), func() R) {
+		var acc = initial
+		return func(v T) { acc = fold(acc, v) }, func() R { return acc }
+	}
+}
+
+// CountAggregator returns an Aggregator counting the elements of a group.
+func CountAggregator[T any]() Aggregator[T, int] {
+	return FoldAggregator(0, func(acc int, _ T) int { return acc + 1 })
+}
+
+// SumAggregator returns an Aggregator summing the values of the elements of a group.
+func SumAggregator[T any, N Number](valueFunc func(T) N) Aggregator[T, N] {
+	return FoldAggregator(0, func(acc N, v T) N { return acc + valueFunc(v) })
+}
+
+// AvgAggregator returns an Aggregator averaging the values of the elements of a group.
+func AvgAggregator[T any, N Number](valueFunc func(T) N) Aggregator[T, float64] {
+	return func() (func(T), func() float64) {
+		var (
+			sum   float64
+			count int
+		)
+
+		return func(v T) {
+				sum += float64(valueFunc(v))
+				count++
+			}, func() float64 {
+				if count == 0 {
+					return 0
+				}
+
+				return sum / float64(count)
+			}
+	}
+}
+
+// MinAggregator returns an Aggregator selecting the smallest value of the elements of a group.
+func MinAggregator[T any, N constraints.Ordered](valueFunc func(T) N) Aggregator[T, N] {
+	return extremumAggregator(valueFunc, Min[N])
+}
+
+// MaxAggregator returns an Aggregator selecting the largest value of the elements of a group.
+func MaxAggregator[T any, N constraints.Ordered](valueFunc func(T) N) Aggregator[T, N] {
+	return extremumAggregator(valueFunc, Max[N])
+}
+
+func extremumAggregator[T any, N constraints.Ordered](valueFunc func(T) N, pick func(N, N) N) Aggregator[T, N] {
+	return func() (func(T), func() N) {
+		var (
+			result N
+			seen   bool
+		)
+
+		return func(v T) {
+				if !seen {
+					result, seen = valueFunc(v), true
+					return
+				}
+
+				result = pick(result, valueFunc(v))
+			}, func() N {
+				return result
+			}
+	}
+}
+
+// FirstAggregator returns an Aggregator selecting the first element of a group.
+func FirstAggregator[T any]() Aggregator[T, T] {
+	return func() (func(T), func() T) {
+		var (
+			result T
+			seen   bool
+		)
+
+		return func(v T) {
+				if !seen {
+					result, seen = v, true
+				}
+			}, func() T {
+				return result
+			}
+	}
+}
+
+// LastAggregator returns an Aggregator selecting the last element of a group.
+func LastAggregator[T any]() Aggregator[T, T] {
+	return FoldAggregator(*new(T), func(_ T, v T) T { return v })
+}
+
+// DistinctAggregator returns an Aggregator collecting the distinct values of the elements of a group
+// in order of their first occurrence.
+func DistinctAggregator[T any, V comparable](valueFunc func(T) V) Aggregator[T, []V] {
+	return func() (func(T), func() []V) {
+		var (
+			seen   = make(map[V]struct{})
+			result []V
+		)
+
+		return func(v T) {
+				var value = valueFunc(v)
+				if _, ok := seen[value]; !ok {
+					seen[value] = struct{}{}
+					result = append(result, value)
+				}
+			}, func() []V {
+				return result
+			}
+	}
+}
+
+// GroupByAggregate groups the elements of the slice by a key returned by the given key function
+// and aggregates every group incrementally, without materializing the groups.
+func GroupByAggregate[S ~[]T, T any, K comparable, R any](source S, keyFunc func(T) K, aggregator Aggregator[T, R]) map[K]R {
+	var groups = groupAggregate(source, aggregator, keyFunc)
+
+	var result = make(map[K]R, len(groups.children))
+	for _, group := range groups.children {
+		result[group.key] = group.result()
+	}
+
+	return result
+}
+
+// GroupByAggregateOrdered is like GroupByAggregate, but returns the groups in order of their first occurrence.
+func GroupByAggregateOrdered[S ~[]T, T any, K comparable, R any](source S, keyFunc func(T) K, aggregator Aggregator[T, R]) []KV[K, R] {
+	return TransformBy(groupAggregate(source, aggregator, keyFunc).children, func(group *aggregateGroup[T, K, R]) KV[K, R] {
+		return KV[K, R]{Key: group.key, Value: group.result()}
+	})
+}
+
+// GroupByAggregate2 groups the elements of the slice by two levels of keys and aggregates every inner group.
+func GroupByAggregate2[S ~[]T, T any, K1, K2 comparable, R any](source S, keyFunc1 func(T) K1, keyFunc2 func(T) K2, aggregator Aggregator[T, R]) map[K1]map[K2]R {
+	var groups = make(map[K1]*aggregateGroup[T, K2, R])
+	for _, v := range source {
+		var key = keyFunc1(v)
+
+		var group, ok = groups[key]
+		if !ok {
+			group = &aggregateGroup[T, K2, R]{}
+			groups[key] = group
+		}
+
+		group.child(keyFunc2(v), aggregator).add(v)
+	}
+
+	var result = make(map[K1]map[K2]R, len(groups))
+	for key, group := range groups {
+		result[key] = make(map[K2]R, len(group.children))
+		for _, child := range group.children {
+			result[key][child.key] = child.result()
+		}
+	}
+
+	return result
+}
+
+// GroupByTree groups the elements of the slice by one level of keys per key function.
+// Every node of the returned forest holds the key of its group and the aggregated value of all
+// elements in it, so inner nodes hold subtotals of their children. Nodes are ordered by first occurrence.
+func GroupByTree[S ~[]T, T any, K comparable, R any](source S, aggregator Aggregator[T, R], keyFuncs ...func(T) K) []*Tree[KV[K, R]] {
+	return TransformBy(groupAggregate(source, aggregator, keyFuncs...).children, (*aggregateGroup[T, K, R]).tree)
+}
+
+type aggregateGroup[T any, K comparable, R any] struct {
+	key      K
+	add      func(T)
+	result   func() R
+	index    map[K]*aggregateGroup[T, K, R]
+	children []*aggregateGroup[T, K, R]
+}
+
+// groupAggregate folds every element into the groups along its keys in a single pass.
+// The returned root only holds the top-level groups and aggregates nothing itself.
+func groupAggregate[S ~[]T, T any, K comparable, R any](source S, aggregator Aggregator[T, R], keyFuncs ...func(T) K) *aggregateGroup[T, K, R] {
+	var root = &aggregateGroup[T, K, R]{}
+
+	for _, v := range source {
+		var group = root
+		for _, keyFunc := range keyFuncs {
+			group = group.child(keyFunc(v), aggregator)
+			group.add(v)
+		}
+	}
+
+	return root
+}
+
+func (g *aggregateGroup[T, K, R]) child(key K, aggregator Aggregator[T, R]) *aggregateGroup[T, K, R] {
+	if child, ok := g.index[key]; ok {
+		return child
+	}
+
+	if g.index == nil {
+		g.index = make(map[K]*aggregateGroup[T, K, R])
+	}
+
+	var child = &aggregateGroup[T, K, R]{key: key}
+	child.add, child.result = aggregator()
+
+	g.index[key] = child
+	g.children = append(g.children, child)
+
+	return child
+}
+
+func (g *aggregateGroup[T, K, R]) tree() *Tree[KV[K, R]] {
+	var result = &Tree[KV[K, R]]{Value: KV[K, R]{Key: g.key, Value: g.result()}}
+	if len(g.children) > 0 {
+		result.Children = TransformBy(g.children, (*aggregateGroup[T, K, R]).tree)
+	}
+
+	return result
+}
@@ -1,6 +1,8 @@
 package collection_test
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"slices"
@@ -35,3 +37,76 @@ func TestGroupBy(t *testing.T) {
 		})
 	}
 }
+
+type sale struct {
+	Region  string
+	Product string
+	Amount  int
+}
+
+var sales = []sale{
+	{"eu", "apple", 10},
+	{"us", "pear", 5},
+	{"eu", "pear", 7},
+	{"eu", "apple", 3},
+	{"us", "apple", 8},
+}
+
+func TestGroupByAggregate(t *testing.T) {
+	var (
+		region = func(s sale) string { return s.Region }
+		amount = func(s sale) int { return s.Amount }
+	)
+
+	cases := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "count", got: collection.GroupByAggregate(sales, region, collection.CountAggregator[sale]()), want: map[string]int{"eu": 3, "us": 2}},
+		{name: "sum", got: collection.GroupByAggregate(sales, region, collection.SumAggregator(amount)), want: map[string]int{"eu": 20, "us": 13}},
+		{name: "avg", got: collection.GroupByAggregate(sales, region, collection.AvgAggregator(amount)), want: map[string]float64{"eu": 20.0 / 3, "us": 6.5}},
+		{name: "min", got: collection.GroupByAggregate(sales, region, collection.MinAggregator(amount)), want: map[string]int{"eu": 3, "us": 5}},
+		{name: "max", got: collection.GroupByAggregate(sales, region, collection.MaxAggregator(amount)), want: map[string]int{"eu": 10, "us": 8}},
+		{name: "first", got: collection.GroupByAggregate(sales, region, collection.FirstAggregator[sale]()), want: map[string]sale{"eu": sales[0], "us": sales[1]}},
+		{name: "last", got: collection.GroupByAggregate(sales, region, collection.LastAggregator[sale]()), want: map[string]sale{"eu": sales[3], "us": sales[4]}},
+		{name: "distinct", got: collection.GroupByAggregate(sales, region, collection.DistinctAggregator(func(s sale) string { return s.Product })), want: map[string][]string{"eu": {"apple", "pear"}, "us": {"pear", "apple"}}},
+		{name: "fold", got: collection.GroupByAggregate(sales, region, collection.FoldAggregator("", func(acc string, s sale) string { return acc + s.Product[:1] })), want: map[string]string{"eu": "apa", "us": "pa"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("GroupByAggregate() = %v; want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGroupByAggregateOrderedAndNested(t *testing.T) {
+	var (
+		region  = func(s sale) string { return s.Region }
+		product = func(s sale) string { return s.Product }
+		total   = collection.SumAggregator(func(s sale) int { return s.Amount })
+	)
+
+	ordered := collection.GroupByAggregateOrdered(sales, product, total)
+	if want := []collection.KV[string, int]{{Key: "apple", Value: 21}, {Key: "pear", Value: 12}}; !slices.Equal(ordered, want) {
+		t.Errorf("GroupByAggregateOrdered() = %v; want %v", ordered, want)
+	}
+
+	nested := collection.GroupByAggregate2(sales, region, product, total)
+	if want := map[string]map[string]int{"eu": {"apple": 13, "pear": 7}, "us": {"pear": 5, "apple": 8}}; !reflect.DeepEqual(nested, want) {
+		t.Errorf("GroupByAggregate2() = %v; want %v", nested, want)
+	}
+
+	var rows []string
+	for _, root := range collection.GroupByTree(sales, total, region, product) {
+		for _, node := range root.Flatten() {
+			rows = append(rows, fmt.Sprintf("%d:%s=%d", node.Depth, node.Value.Key, node.Value.Value))
+		}
+	}
+
+	if want := []string{"0:eu=20", "1:apple=13", "1:pear=7", "0:us=13", "1:pear=5", "1:apple=8"}; !slices.Equal(rows, want) {
+		t.Errorf("GroupByTree() = %v; want %v", rows, want)
+	}
+}
@@ -0,0 +1,302 @@
+package collection
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrIncompatibleHistogram is returned when merging histograms with different boundaries.
+var ErrIncompatibleHistogram = errors.New("collection: incompatible histogram boundaries")
+
+// Bucketing computes increasing histogram boundaries for the given values.
+type Bucketing func(values []float64) []float64
+
+// FixedWidthBuckets returns a Bucketing with buckets of the given width aligned to multiples of it,
+// covering all values.
+func FixedWidthBuckets(width float64) Bucketing {
+	return func(values []float64) []float64 {
+		if len(values) == 0 || !(width > 0) {
+			return nil
+		}
+
+		var (
+			low   = math.Floor(slices.Min(values)/width) * width
+			count = int(math.Floor((slices.Max(values)-low)/width)) + 1
+		)
+
+		var result = make([]float64, count+1)
+		for i := range result {
+			result[i] = low + float64(i)*width
+		}
+
+		return result
+	}
+}
+
+// ExplicitBuckets returns a Bucketing with the given boundaries regardless of the values.
+// The boundaries are sorted and deduplicated.
+func ExplicitBuckets(boundaries ...float64) Bucketing {
+	var result = slices.Clone(boundaries)
+	slices.Sort(result)
+	result = slices.Compact(result)
+
+	return func([]float64) []float64 {
+		return result
+	}
+}
+
+// LogarithmicBuckets returns a Bucketing with boundaries at consecutive powers of base,
+// covering all positive values. Non-positive values fall below the first boundary.
+func LogarithmicBuckets(base float64) Bucketing {
+	return func(values []float64) []float64 {
+		var positive = FilterBy(values, func(v float64) bool { return v > 0 })
+		if len(positive) == 0 || !(base > 1) {
+			return nil
+		}
+
+		var (
+			exponent = func(v float64) float64 {
+				var k = math.Floor(math.Log(v) / math.Log(base))
+				if math.Pow(base, k+1) <= v {
+					return k + 1
+				}
+
+				if math.Pow(base, k) > v {
+					return k - 1
+				}
+
+				return k
+			}
+			low, high = exponent(slices.Min(positive)), exponent(slices.Max(positive))
+		)
+
+		var result []float64
+		for k := low; k <= high+1; k++ {
+			result = append(result, math.Pow(base, k))
+		}
+
+		return result
+	}
+}
+
+// QuantileBuckets returns a Bucketing with boundaries at the quantiles of the values,
+// so every bucket holds about the same number of values. Buckets collapse if values repeat.
+func QuantileBuckets(count int) Bucketing {
+	return func(values []float64) []float64 {
+		if len(values) == 0 || count < 1 {
+			return nil
+		}
+
+		var result = make([]float64, count+1)
+		for i := range result {
+			result[i] = Percentile(values, float64(i)*100/float64(count), InterpolationLinear)
+		}
+
+		return slices.Compact(result)
+	}
+}
+
+// HistogramBucket is a bucket [Low, High) of a histogram with the number of values it holds.
+// The last bucket also holds values equal to its High.
+type HistogramBucket struct {
+	Low   float64
+	High  float64
+	Count int
+}
+
+// Histogram counts values in buckets between increasing boundaries.
+// Values below the first or above the last boundary are counted as underflow and overflow.
+type Histogram struct {
+	boundaries []float64
+	counts     []int
+	underflow  int
+	overflow   int
+}
+
+// NewHistogram returns a histogram of the values with boundaries computed by the bucketing.
+func NewHistogram[S ~[]T, T Number](source S, bucketing Bucketing) *Histogram {
+	return HistogramBy(source, func(v T) T { return v }, bucketing)
+}
+
+// HistogramBy returns a histogram of the values returned by the value function for each element,
+// with boundaries computed by the bucketing.
+func HistogramBy[S ~[]T, T any, V Number](source S, valueFunc func(T) V, bucketing Bucketing) *Histogram {
+	var values = TransformBy(source, func(v T) float64 { return float64(valueFunc(v)) })
+	values = FilterBy(values, func(v float64) bool { return !math.IsNaN(v) })
+
+	var (
+		boundaries = bucketing(values)
+		result     = &Histogram{boundaries: slices.Clone(boundaries), counts: make([]int, Max(len(boundaries)-1, 0))}
+	)
+
+	for _, v := range values {
+		result.Add(v)
+	}
+
+	return result
+}
+
+// Add counts the value in its bucket. NaN values are ignored.
+func (h *Histogram) Add(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+
+	var i = sort.Search(len(h.boundaries), func(i int) bool { return h.boundaries[i] > value }) - 1
+
+	switch {
+	case i < 0:
+		h.underflow++
+	case i < len(h.counts):
+		h.counts[i]++
+	case len(h.counts) > 0 && value == h.boundaries[len(h.counts)]:
+		h.counts[len(h.counts)-1]++
+	default:
+		h.overflow++
+	}
+}
+
+// Boundaries returns the boundaries of the buckets.
+func (h *Histogram) Boundaries() []float64 {
+	return slices.Clone(h.boundaries)
+}
+
+// Buckets returns the buckets in ascending order.
+func (h *Histogram) Buckets() []HistogramBucket {
+	var result = make([]HistogramBucket, len(h.counts))
+	for i, count := range h.counts {
+		result[i] = HistogramBucket{Low: h.boundaries[i], High: h.boundaries[i+1], Count: count}
+	}
+
+	return result
+}
+
+// Counts returns the number of values in every bucket.
+func (h *Histogram) Counts() []int {
+	return slices.Clone(h.counts)
+}
+
+// CumulativeCounts returns for every bucket the number of values up to its upper boundary, including the underflow.
+func (h *Histogram) CumulativeCounts() []int {
+	var (
+		result = make([]int, len(h.counts))
+		total  = h.underflow
+	)
+
+	for i, count := range h.counts {
+		total += count
+		result[i] = total
+	}
+
+	return result
+}
+
+// Underflow returns the number of values below the first boundary.
+func (h *Histogram) Underflow() int {
+	return h.underflow
+}
+
+// Overflow returns the number of values above the last boundary.
+func (h *Histogram) Overflow() int {
+	return h.overflow
+}
+
+// Total returns the number of values counted, including underflow and overflow.
+func (h *Histogram) Total() int {
+	return h.underflow + Sum(h.counts) + h.overflow
+}
+
+// Merge adds the counts of other to the histogram. Both histograms must have the same boundaries.
+func (h *Histogram) Merge(other *Histogram) error {
+	if !Equal(h.boundaries, other.boundaries) {
+		return ErrIncompatibleHistogram
+	}
+
+	for i, count := range other.counts {
+		h.counts[i] += count
+	}
+
+	h.underflow += other.underflow
+	h.overflow += other.overflow
+
+	return nil
+}
+
+// TimeResolution is the length of the buckets of BucketByTime.
+type TimeResolution int
+
+const (
+	// TimeMinute buckets by wall-clock minute.
+	TimeMinute TimeResolution = iota
+	// TimeHour buckets by wall-clock hour.
+	TimeHour
+	// TimeDay buckets by calendar day.
+	TimeDay
+)
+
+// TimeBucket is a time range [Start, End) with the elements that fall into it.
+type TimeBucket[T any] struct {
+	Start time.Time
+	End   time.Time
+	Items []T
+}
+
+// BucketByTime groups the elements by the minute, hour or day of the time returned by the time function,
+// as seen in the given location. Days follow the calendar of the location, so they may be shorter or
+// longer than 24 hours around daylight saving transitions. Only non-empty buckets are returned, in ascending order.
+func BucketByTime[S ~[]T, T any](source S, timeFunc func(T) time.Time, resolution TimeResolution, loc *time.Location) []TimeBucket[T] {
+	var (
+		buckets = make(map[int64]*TimeBucket[T])
+		result  []TimeBucket[T]
+	)
+
+	for _, v := range source {
+		var start, end = timeBucketBounds(timeFunc(v).In(loc), resolution)
+
+		var bucket, ok = buckets[start.UnixNano()]
+		if !ok {
+			bucket = &TimeBucket[T]{Start: start, End: end}
+			buckets[start.UnixNano()] = bucket
+		}
+
+		bucket.Items = append(bucket.Items, v)
+	}
+
+	for _, bucket := range buckets {
+		result = append(result, *bucket)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+
+	return result
+}
+
+func timeBucketBounds(t time.Time, resolution TimeResolution) (time.Time, time.Time) {
+	switch resolution {
+	case TimeMinute, TimeHour:
+		// Truncate in wall-clock time, so zones with fractional-hour offsets and repeated
+		// hours around daylight saving transitions get buckets of their own.
+		var length = time.Minute
+		if resolution == TimeHour {
+			length = time.Hour
+		}
+
+		var (
+			_, offset = t.Zone()
+			shift     = time.Duration(offset) * time.Second
+			start     = t.Add(shift).Truncate(length).Add(-shift)
+		)
+
+		return start, start.Add(length)
+	default:
+		var (
+			year, month, day = t.Date()
+			start            = time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		)
+
+		return start, time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+	}
+}
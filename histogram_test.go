@@ -0,0 +1,159 @@
+package collection_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestHistogramBucketing(t *testing.T) {
+	cases := []struct {
+		name           string
+		source         []float64
+		bucketing      collection.Bucketing
+		wantBoundaries []float64
+		wantCounts     []int
+		wantUnderflow  int
+		wantOverflow   int
+	}{
+		{
+			name:           "fixed width",
+			source:         []float64{3, 7, 12, 19, 20},
+			bucketing:      collection.FixedWidthBuckets(10),
+			wantBoundaries: []float64{0, 10, 20, 30},
+			wantCounts:     []int{2, 2, 1},
+		},
+		{
+			name:           "explicit",
+			source:         []float64{-1, 5, 10, 50, 100, 101},
+			bucketing:      collection.ExplicitBuckets(100, 0, 10, 10),
+			wantBoundaries: []float64{0, 10, 100},
+			wantCounts:     []int{1, 3},
+			wantUnderflow:  1,
+			wantOverflow:   1,
+		},
+		{
+			name:           "logarithmic",
+			source:         []float64{0, 1, 9, 10, 999, 1000},
+			bucketing:      collection.LogarithmicBuckets(10),
+			wantBoundaries: []float64{1, 10, 100, 1000, 10000},
+			wantCounts:     []int{2, 1, 1, 1},
+			wantUnderflow:  1,
+		},
+		{
+			name:           "quantile",
+			source:         []float64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			bucketing:      collection.QuantileBuckets(4),
+			wantBoundaries: []float64{1, 3, 5, 7, 9},
+			wantCounts:     []int{2, 2, 2, 3},
+		},
+		{
+			name:           "quantile with repeated values",
+			source:         []float64{1, 1, 1, 1, 2},
+			bucketing:      collection.QuantileBuckets(4),
+			wantBoundaries: []float64{1, 2},
+			wantCounts:     []int{5},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := collection.NewHistogram(tc.source, tc.bucketing)
+
+			if got := h.Boundaries(); !slices.Equal(got, tc.wantBoundaries) {
+				t.Errorf("Boundaries() = %v; want %v", got, tc.wantBoundaries)
+			}
+
+			if got := h.Counts(); !slices.Equal(got, tc.wantCounts) {
+				t.Errorf("Counts() = %v; want %v", got, tc.wantCounts)
+			}
+
+			if h.Underflow() != tc.wantUnderflow || h.Overflow() != tc.wantOverflow || h.Total() != len(tc.source) {
+				t.Errorf("Underflow() = %v, Overflow() = %v, Total() = %v", h.Underflow(), h.Overflow(), h.Total())
+			}
+		})
+	}
+}
+
+func TestHistogramByAndMerge(t *testing.T) {
+	type request struct {
+		path    string
+		latency time.Duration
+	}
+
+	var (
+		bucketing = collection.ExplicitBuckets(0, 100, 250, 1000)
+		latency   = func(r request) int64 { return r.latency.Milliseconds() }
+		shardA    = collection.HistogramBy([]request{{"/a", 20 * time.Millisecond}, {"/b", 300 * time.Millisecond}}, latency, bucketing)
+		shardB    = collection.HistogramBy([]request{{"/a", 120 * time.Millisecond}, {"/c", 2 * time.Second}}, latency, bucketing)
+	)
+
+	if err := shardA.Merge(shardB); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	want := []collection.HistogramBucket{{Low: 0, High: 100, Count: 1}, {Low: 100, High: 250, Count: 1}, {Low: 250, High: 1000, Count: 1}}
+	if got := shardA.Buckets(); !slices.Equal(got, want) {
+		t.Errorf("Buckets() = %v; want %v", got, want)
+	}
+
+	if got := shardA.CumulativeCounts(); !slices.Equal(got, []int{1, 2, 3}) || shardA.Overflow() != 1 {
+		t.Errorf("CumulativeCounts() = %v, Overflow() = %v", got, shardA.Overflow())
+	}
+
+	other := collection.NewHistogram([]int{1}, collection.ExplicitBuckets(0, 10))
+	if err := shardA.Merge(other); !errors.Is(err, collection.ErrIncompatibleHistogram) {
+		t.Errorf("Merge() of different boundaries error = %v", err)
+	}
+}
+
+func TestBucketByTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	var (
+		kolkata = time.FixedZone("IST", 5*3600+1800)
+		events  = []time.Time{
+			time.Date(2024, 11, 3, 5, 10, 0, 0, time.UTC), // 01:10 EDT
+			time.Date(2024, 11, 3, 5, 50, 0, 0, time.UTC), // 01:50 EDT
+			time.Date(2024, 11, 3, 6, 20, 0, 0, time.UTC), // 01:20 EST, the repeated hour
+			time.Date(2024, 11, 4, 4, 59, 0, 0, time.UTC), // 23:59 EST on Nov 3
+			time.Date(2024, 11, 4, 5, 0, 30, 0, time.UTC), // 00:00 EST on Nov 4
+		}
+		identity = func(v time.Time) time.Time { return v }
+	)
+
+	starts := func(buckets []collection.TimeBucket[time.Time]) []string {
+		return collection.TransformBy(buckets, func(b collection.TimeBucket[time.Time]) string {
+			return b.Start.Format(time.RFC3339)
+		})
+	}
+
+	sizes := func(buckets []collection.TimeBucket[time.Time]) []int {
+		return collection.TransformBy(buckets, func(b collection.TimeBucket[time.Time]) int { return len(b.Items) })
+	}
+
+	hours := collection.BucketByTime(events, identity, collection.TimeHour, newYork)
+	if want := []string{"2024-11-03T01:00:00-04:00", "2024-11-03T01:00:00-05:00", "2024-11-03T23:00:00-05:00", "2024-11-04T00:00:00-05:00"}; !slices.Equal(starts(hours), want) {
+		t.Errorf("hour buckets = %v; want %v", starts(hours), want)
+	}
+
+	days := collection.BucketByTime(events, identity, collection.TimeDay, newYork)
+	if !slices.Equal(sizes(days), []int{4, 1}) || days[0].End.Sub(days[0].Start) != 25*time.Hour {
+		t.Errorf("day bucket sizes = %v, first day length = %v", sizes(days), days[0].End.Sub(days[0].Start))
+	}
+
+	minutes := collection.BucketByTime(events[3:], identity, collection.TimeMinute, kolkata)
+	if want := []string{"2024-11-04T10:29:00+05:30", "2024-11-04T10:30:00+05:30"}; !slices.Equal(starts(minutes), want) {
+		t.Errorf("minute buckets = %v; want %v", starts(minutes), want)
+	}
+
+	halfHourZone := collection.BucketByTime(events[:1], identity, collection.TimeHour, kolkata)
+	if got := halfHourZone[0].Start.Format(time.RFC3339); got != "2024-11-03T10:00:00+05:30" {
+		t.Errorf("hour bucket in IST = %v; want 2024-11-03T10:00:00+05:30", got)
+	}
+}
@@ -0,0 +1,372 @@
+package collection
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is a closed interval [Low, High] holding a value.
+type Interval[K constraints.Ordered, V any] struct {
+	Low   K
+	High  K
+	Value V
+}
+
+// Contains reports whether the point lies within the interval.
+func (i Interval[K, V]) Contains(point K) bool {
+	return i.Low <= point && point <= i.High
+}
+
+// Overlaps reports whether the interval shares at least one point with [low, high].
+func (i Interval[K, V]) Overlaps(low, high K) bool {
+	return i.Low <= high && low <= i.High
+}
+
+// IntervalTree stores closed intervals and answers stabbing and overlap queries in
+// O(log n + m) time. It is an AVL tree ordered by interval bounds and augmented with
+// the maximal upper bound of every subtree. Intervals with the same bounds share a node,
+// so several values may be stored for the same bounds.
+type IntervalTree[K constraints.Ordered, V any] struct {
+	root *intervalNode[K, V]
+	size int
+}
+
+type intervalNode[K constraints.Ordered, V any] struct {
+	low, high   K
+	values      []V
+	max         K
+	height      int
+	left, right *intervalNode[K, V]
+}
+
+// NewIntervalTree returns an empty IntervalTree.
+func NewIntervalTree[K constraints.Ordered, V any]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{}
+}
+
+// Len returns the number of intervals.
+func (t *IntervalTree[K, V]) Len() int {
+	return t.size
+}
+
+// Insert stores the interval [low, high] with the value. Intervals with the same bounds
+// are kept in insertion order. Intervals with high < low are ignored.
+func (t *IntervalTree[K, V]) Insert(low, high K, value V) {
+	if high < low {
+		return
+	}
+
+	t.root = t.root.insert(low, high, value)
+	t.size++
+}
+
+// Delete removes the intervals with the given bounds whose value matches, or all of them
+// if match is nil, and returns the number of intervals removed.
+func (t *IntervalTree[K, V]) Delete(low, high K, match func(V) bool) (deleted int) {
+	t.root, deleted = t.root.delete(low, high, match)
+	t.size -= deleted
+
+	return deleted
+}
+
+// Stab returns the intervals containing the point ordered by their bounds.
+func (t *IntervalTree[K, V]) Stab(point K) []Interval[K, V] {
+	return t.Overlap(point, point)
+}
+
+// Overlap returns the intervals sharing at least one point with [low, high] ordered by their bounds.
+func (t *IntervalTree[K, V]) Overlap(low, high K) []Interval[K, V] {
+	var result []Interval[K, V]
+	t.root.overlap(low, high, &result)
+	return result
+}
+
+// Intervals returns all intervals ordered by their bounds.
+func (t *IntervalTree[K, V]) Intervals() []Interval[K, V] {
+	var result = make([]Interval[K, V], 0, t.size)
+	t.root.each(func(n *intervalNode[K, V]) { result = n.appendIntervals(result) })
+	return result
+}
+
+func compareIntervalBounds[K constraints.Ordered](lowA, highA, lowB, highB K) int {
+	switch {
+	case lowA < lowB:
+		return -1
+	case lowA > lowB:
+		return 1
+	case highA < highB:
+		return -1
+	case highA > highB:
+		return 1
+	}
+
+	return 0
+}
+
+func (n *intervalNode[K, V]) insert(low, high K, value V) *intervalNode[K, V] {
+	if n == nil {
+		return &intervalNode[K, V]{low: low, high: high, values: []V{value}, max: high, height: 1}
+	}
+
+	switch compareIntervalBounds(low, high, n.low, n.high) {
+	case -1:
+		n.left = n.left.insert(low, high, value)
+	case 1:
+		n.right = n.right.insert(low, high, value)
+	default:
+		n.values = append(n.values, value)
+		return n
+	}
+
+	return n.rebalance()
+}
+
+func (n *intervalNode[K, V]) delete(low, high K, match func(V) bool) (*intervalNode[K, V], int) {
+	if n == nil {
+		return nil, 0
+	}
+
+	var deleted int
+
+	switch compareIntervalBounds(low, high, n.low, n.high) {
+	case -1:
+		n.left, deleted = n.left.delete(low, high, match)
+	case 1:
+		n.right, deleted = n.right.delete(low, high, match)
+	default:
+		var kept []V
+		if match != nil {
+			kept = FilterBy(n.values, func(v V) bool { return !match(v) })
+		}
+
+		deleted = len(n.values) - len(kept)
+		if len(kept) > 0 {
+			n.values = kept
+			return n, deleted
+		}
+
+		if n.left == nil {
+			return n.right, deleted
+		}
+
+		if n.right == nil {
+			return n.left, deleted
+		}
+
+		var successor = n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+
+		n.low, n.high, n.values = successor.low, successor.high, successor.values
+		n.right, _ = n.right.delete(successor.low, successor.high, nil)
+	}
+
+	return n.rebalance(), deleted
+}
+
+func (n *intervalNode[K, V]) overlap(low, high K, result *[]Interval[K, V]) {
+	if n == nil || n.max < low {
+		return
+	}
+
+	n.left.overlap(low, high, result)
+
+	if n.low <= high && low <= n.high {
+		*result = n.appendIntervals(*result)
+	}
+
+	if n.low <= high {
+		n.right.overlap(low, high, result)
+	}
+}
+
+func (n *intervalNode[K, V]) appendIntervals(result []Interval[K, V]) []Interval[K, V] {
+	for _, value := range n.values {
+		result = append(result, Interval[K, V]{Low: n.low, High: n.high, Value: value})
+	}
+
+	return result
+}
+
+func (n *intervalNode[K, V]) each(do func(*intervalNode[K, V])) {
+	if n == nil {
+		return
+	}
+
+	n.left.each(do)
+	do(n)
+	n.right.each(do)
+}
+
+func (n *intervalNode[K, V]) heightOf() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+func (n *intervalNode[K, V]) update() {
+	n.height = Max(n.left.heightOf(), n.right.heightOf()) + 1
+	n.max = n.high
+
+	if n.left != nil {
+		n.max = Max(n.max, n.left.max)
+	}
+
+	if n.right != nil {
+		n.max = Max(n.max, n.right.max)
+	}
+}
+
+func (n *intervalNode[K, V]) rebalance() *intervalNode[K, V] {
+	n.update()
+
+	switch balance := n.left.heightOf() - n.right.heightOf(); {
+	case balance > 1:
+		if n.left.left.heightOf() < n.left.right.heightOf() {
+			n.left = n.left.rotateLeft()
+		}
+
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.right.heightOf() < n.right.left.heightOf() {
+			n.right = n.right.rotateRight()
+		}
+
+		return n.rotateLeft()
+	}
+
+	return n
+}
+
+func (n *intervalNode[K, V]) rotateLeft() *intervalNode[K, V] {
+	var pivot = n.right
+	n.right, pivot.left = pivot.left, n
+	n.update()
+	pivot.update()
+	return pivot
+}
+
+func (n *intervalNode[K, V]) rotateRight() *intervalNode[K, V] {
+	var pivot = n.left
+	n.left, pivot.right = pivot.right, n
+	n.update()
+	pivot.update()
+	return pivot
+}
+
+// Range is a half-open range [From, To) holding a value.
+type Range[K constraints.Ordered, V any] struct {
+	From  K
+	To    K
+	Value V
+}
+
+// RangeMap maps non-overlapping half-open ranges of keys to values. Setting a range
+// splits the ranges it overwrites, and adjacent ranges with equal values are merged.
+type RangeMap[K constraints.Ordered, V comparable] struct {
+	ranges []Range[K, V]
+}
+
+// NewRangeMap returns an empty RangeMap.
+func NewRangeMap[K constraints.Ordered, V comparable]() *RangeMap[K, V] {
+	return &RangeMap[K, V]{}
+}
+
+// Set maps every key in [from, to) to the value. Empty ranges are ignored.
+func (m *RangeMap[K, V]) Set(from, to K, value V) {
+	if from >= to {
+		return
+	}
+
+	m.replace(from, to, []Range[K, V]{{From: from, To: to, Value: value}})
+}
+
+// Delete removes the mapping of every key in [from, to).
+func (m *RangeMap[K, V]) Delete(from, to K) {
+	if from >= to {
+		return
+	}
+
+	m.replace(from, to, nil)
+}
+
+// Get returns the value mapped to the key.
+func (m *RangeMap[K, V]) Get(key K) (value V, ok bool) {
+	var i = sort.Search(len(m.ranges), func(i int) bool { return m.ranges[i].To > key })
+	if i < len(m.ranges) && m.ranges[i].From <= key {
+		return m.ranges[i].Value, true
+	}
+
+	return value, false
+}
+
+// Overlap returns the ranges intersecting [from, to), clipped to it.
+func (m *RangeMap[K, V]) Overlap(from, to K) []Range[K, V] {
+	var result []Range[K, V]
+	for i := sort.Search(len(m.ranges), func(i int) bool { return m.ranges[i].To > from }); i < len(m.ranges) && m.ranges[i].From < to; i++ {
+		var r = m.ranges[i]
+		result = append(result, Range[K, V]{From: Max(r.From, from), To: Min(r.To, to), Value: r.Value})
+	}
+
+	return result
+}
+
+// Ranges returns all ranges in ascending order.
+func (m *RangeMap[K, V]) Ranges() []Range[K, V] {
+	return Clone(m.ranges)
+}
+
+// Len returns the number of ranges.
+func (m *RangeMap[K, V]) Len() int {
+	return len(m.ranges)
+}
+
+func (m *RangeMap[K, V]) replace(from, to K, inserted []Range[K, V]) {
+	var (
+		i = sort.Search(len(m.ranges), func(i int) bool { return m.ranges[i].To > from })
+		j = sort.Search(len(m.ranges), func(i int) bool { return m.ranges[i].From >= to })
+	)
+
+	var pieces = make([]Range[K, V], 0, len(inserted)+2)
+
+	if i < j && m.ranges[i].From < from {
+		pieces = append(pieces, Range[K, V]{From: m.ranges[i].From, To: from, Value: m.ranges[i].Value})
+	}
+
+	pieces = append(pieces, inserted...)
+
+	if i < j && m.ranges[j-1].To > to {
+		pieces = append(pieces, Range[K, V]{From: to, To: m.ranges[j-1].To, Value: m.ranges[j-1].Value})
+	}
+
+	var ranges = make([]Range[K, V], 0, len(m.ranges)-(j-i)+len(pieces))
+	ranges = append(ranges, m.ranges[:i]...)
+	ranges = append(ranges, pieces...)
+	ranges = append(ranges, m.ranges[j:]...)
+
+	m.ranges = coalesceRanges(ranges, Max(i-1, 0), Min(i+len(pieces)+1, len(ranges)))
+}
+
+// coalesceRanges merges touching ranges with equal values within ranges[from:to].
+func coalesceRanges[K constraints.Ordered, V comparable](ranges []Range[K, V], from, to int) []Range[K, V] {
+	if len(ranges) == 0 {
+		return ranges
+	}
+
+	var result = ranges[:from+1]
+	for _, r := range ranges[from+1 : to] {
+		var last = &result[len(result)-1]
+		if last.To == r.From && last.Value == r.Value {
+			last.To = r.To
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return append(result, ranges[to:]...)
+}
@@ -0,0 +1,194 @@
+package collection_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func intervalLabels(intervals []collection.Interval[int, string]) []string {
+	return collection.TransformBy(intervals, func(i collection.Interval[int, string]) string { return i.Value })
+}
+
+func TestIntervalTree(t *testing.T) {
+	tree := collection.NewIntervalTree[int, string]()
+	tree.Insert(9, 17, "morning")
+	tree.Insert(12, 13, "lunch")
+	tree.Insert(17, 22, "evening")
+	tree.Insert(0, 24, "day")
+	tree.Insert(5, 1, "invalid")
+
+	tree.Insert(12, 13, "standup")
+
+	if tree.Len() != 5 {
+		t.Errorf("Len() = %v; want 5 with both intervals of the same bounds", tree.Len())
+	}
+
+	cases := []struct {
+		name string
+		got  []collection.Interval[int, string]
+		want []string
+	}{
+		{name: "stab inside", got: tree.Stab(12), want: []string{"day", "morning", "lunch", "standup"}},
+		{name: "stab boundary", got: tree.Stab(17), want: []string{"day", "morning", "evening"}},
+		{name: "stab outside", got: tree.Stab(30), want: nil},
+		{name: "overlap", got: tree.Overlap(13, 18), want: []string{"day", "morning", "lunch", "standup", "evening"}},
+		{name: "overlap after", got: tree.Overlap(23, 25), want: []string{"day"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := intervalLabels(tc.got); !slices.Equal(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	if tree.Delete(0, 24, nil) != 1 || tree.Delete(0, 24, nil) != 0 {
+		t.Errorf("Delete(0, 24) should succeed exactly once")
+	}
+
+	if got := tree.Delete(12, 13, func(v string) bool { return v == "lunch" }); got != 1 || tree.Len() != 3 {
+		t.Errorf("Delete(12, 13, lunch) = %v, Len() = %v; want 1, 3", got, tree.Len())
+	}
+
+	if got := intervalLabels(tree.Stab(12)); !slices.Equal(got, []string{"morning", "standup"}) {
+		t.Errorf("Stab(12) after Delete = %v", got)
+	}
+}
+
+func TestIntervalTreeMatchesLinearScan(t *testing.T) {
+	var (
+		rnd       = rand.New(rand.NewSource(7))
+		tree      = collection.NewIntervalTree[int, int]()
+		intervals = make(map[[2]int][]int)
+		size      int
+	)
+
+	for i := 0; i < 3000; i++ {
+		low := rnd.Intn(1000)
+		bounds := [2]int{low, low + rnd.Intn(50)}
+
+		if rnd.Intn(4) == 0 {
+			want := len(intervals[bounds])
+			if got := tree.Delete(bounds[0], bounds[1], nil); got != want {
+				t.Fatalf("Delete(%v) = %v; want %v", bounds, got, want)
+			}
+			delete(intervals, bounds)
+			size -= want
+		} else {
+			tree.Insert(bounds[0], bounds[1], i)
+			intervals[bounds] = append(intervals[bounds], i)
+			size++
+		}
+	}
+
+	if tree.Len() != size {
+		t.Fatalf("Len() = %v; want %v", tree.Len(), size)
+	}
+
+	for q := 0; q < 200; q++ {
+		low := rnd.Intn(1100) - 50
+		high := low + rnd.Intn(30)
+
+		var want int
+		for bounds, values := range intervals {
+			if bounds[0] <= high && low <= bounds[1] {
+				want += len(values)
+			}
+		}
+
+		got := tree.Overlap(low, high)
+		if len(got) != want {
+			t.Fatalf("Overlap(%v, %v) returned %v intervals; want %v", low, high, len(got), want)
+		}
+
+		for _, i := range got {
+			if !slices.Contains(intervals[[2]int{i.Low, i.High}], i.Value) {
+				t.Fatalf("Overlap() returned stale interval %v", i)
+			}
+		}
+	}
+}
+
+func TestRangeMap(t *testing.T) {
+	type r = collection.Range[int, string]
+
+	cases := []struct {
+		name string
+		do   func(m *collection.RangeMap[int, string])
+		want []r
+	}{
+		{name: "merge adjacent", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(0, 10, "a")
+			m.Set(10, 20, "a")
+			m.Set(30, 40, "a")
+		}, want: []r{{0, 20, "a"}, {30, 40, "a"}}},
+		{name: "split on overwrite", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(0, 100, "a")
+			m.Set(40, 60, "b")
+		}, want: []r{{0, 40, "a"}, {40, 60, "b"}, {60, 100, "a"}}},
+		{name: "overwrite many", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(0, 10, "a")
+			m.Set(10, 20, "b")
+			m.Set(20, 30, "c")
+			m.Set(5, 25, "d")
+		}, want: []r{{0, 5, "a"}, {5, 25, "d"}, {25, 30, "c"}}},
+		{name: "overwrite restores merge", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(0, 100, "a")
+			m.Set(40, 60, "b")
+			m.Set(40, 60, "a")
+		}, want: []r{{0, 100, "a"}}},
+		{name: "delete splits", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(0, 100, "a")
+			m.Delete(10, 20)
+			m.Delete(90, 200)
+		}, want: []r{{0, 10, "a"}, {20, 90, "a"}}},
+		{name: "empty range ignored", do: func(m *collection.RangeMap[int, string]) {
+			m.Set(10, 10, "a")
+			m.Delete(5, 1)
+		}, want: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := collection.NewRangeMap[int, string]()
+			tc.do(m)
+
+			if got := m.Ranges(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Ranges() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRangeMapLookup(t *testing.T) {
+	m := collection.NewRangeMap[uint32, string]()
+	m.Set(0x0A000000, 0x0B000000, "private")
+	m.Set(0xC0A80000, 0xC0A90000, "lan")
+
+	cases := []struct {
+		key  uint32
+		want string
+		ok   bool
+	}{
+		{key: 0x0A000001, want: "private", ok: true},
+		{key: 0x0B000000, ok: false},
+		{key: 0xC0A80101, want: "lan", ok: true},
+		{key: 0x08080808, ok: false},
+	}
+
+	for _, tc := range cases {
+		if got, ok := m.Get(tc.key); got != tc.want || ok != tc.ok {
+			t.Errorf("Get(%x) = (%v, %v); want (%v, %v)", tc.key, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	want := []collection.Range[uint32, string]{{From: 0x0A800000, To: 0x0B000000, Value: "private"}}
+	if got := m.Overlap(0x0A800000, 0x0C000000); !reflect.DeepEqual(got, want) {
+		t.Errorf("Overlap() = %v; want %v", got, want)
+	}
+}
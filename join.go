@@ -0,0 +1,184 @@
+package collection
+
+import "golang.org/x/exp/constraints"
+
+// InnerJoin returns the projection of every pair of left and right elements with equal keys.
+// Every left element is paired with all matching right elements, so duplicate keys on both sides
+// produce their cross product. The result follows the order of left, then of right.
+func InnerJoin[L ~[]T, R ~[]U, T, U any, K comparable, V any](left L, right R, leftKey func(T) K, rightKey func(U) K, project func(T, U) V) []V {
+	var (
+		index  = joinIndex(right, rightKey)
+		result []V
+	)
+
+	for _, l := range left {
+		for _, i := range index[leftKey(l)] {
+			result = append(result, project(l, right[i]))
+		}
+	}
+
+	return result
+}
+
+// LeftJoin is like InnerJoin, but also projects left elements without a match, passing a nil right element.
+func LeftJoin[L ~[]T, R ~[]U, T, U any, K comparable, V any](left L, right R, leftKey func(T) K, rightKey func(U) K, project func(T, *U) V) []V {
+	var (
+		index  = joinIndex(right, rightKey)
+		result []V
+	)
+
+	for _, l := range left {
+		var matches = index[leftKey(l)]
+		if len(matches) == 0 {
+			result = append(result, project(l, nil))
+			continue
+		}
+
+		for _, i := range matches {
+			var r = right[i]
+			result = append(result, project(l, &r))
+		}
+	}
+
+	return result
+}
+
+// RightJoin is like InnerJoin, but also projects right elements without a match, passing a nil left element.
+// The result follows the order of right, then of left.
+func RightJoin[L ~[]T, R ~[]U, T, U any, K comparable, V any](left L, right R, leftKey func(T) K, rightKey func(U) K, project func(*T, U) V) []V {
+	return LeftJoin(right, left, rightKey, leftKey, func(r U, l *T) V {
+		return project(l, r)
+	})
+}
+
+// FullOuterJoin is like LeftJoin, but also projects right elements without a match,
+// passing a nil left element. Unmatched right elements follow the rest of the result in the order of right.
+func FullOuterJoin[L ~[]T, R ~[]U, T, U any, K comparable, V any](left L, right R, leftKey func(T) K, rightKey func(U) K, project func(*T, *U) V) []V {
+	var (
+		index   = joinIndex(right, rightKey)
+		matched = make([]bool, len(right))
+		result  []V
+	)
+
+	for _, l := range left {
+		var (
+			l       = l
+			matches = index[leftKey(l)]
+		)
+
+		if len(matches) == 0 {
+			result = append(result, project(&l, nil))
+			continue
+		}
+
+		for _, i := range matches {
+			var r = right[i]
+			matched[i] = true
+			result = append(result, project(&l, &r))
+		}
+	}
+
+	for i, r := range right {
+		if !matched[i] {
+			var r = r
+			result = append(result, project(nil, &r))
+		}
+	}
+
+	return result
+}
+
+// SemiJoin returns the left elements that have at least one right element with an equal key.
+// Every left element is returned at most once, regardless of the number of matches.
+func SemiJoin[L ~[]T, R ~[]U, T, U any, K comparable](left L, right R, leftKey func(T) K, rightKey func(U) K) L {
+	var keys = InFilter(TransformBy(right, rightKey), true)
+	return FilterBy(left, func(v T) bool { return keys(leftKey(v)) })
+}
+
+// AntiJoin returns the left elements that have no right element with an equal key.
+func AntiJoin[L ~[]T, R ~[]U, T, U any, K comparable](left L, right R, leftKey func(T) K, rightKey func(U) K) L {
+	var keys = InFilter(TransformBy(right, rightKey), false)
+	return FilterBy(left, func(v T) bool { return keys(leftKey(v)) })
+}
+
+// joinIndex returns the positions of the elements by their keys.
+func joinIndex[S ~[]T, T any, K comparable](source S, keyFunc func(T) K) map[K][]int {
+	var result = make(map[K][]int, len(source))
+	for i, v := range source {
+		var key = keyFunc(v)
+		result[key] = append(result[key], i)
+	}
+
+	return result
+}
+
+// JoinKind selects which unmatched elements MergeJoin keeps.
+type JoinKind int
+
+const (
+	// JoinInner keeps only matching pairs.
+	JoinInner JoinKind = iota
+	// JoinLeft also keeps unmatched left elements.
+	JoinLeft
+	// JoinRight also keeps unmatched right elements.
+	JoinRight
+	// JoinFullOuter keeps unmatched elements of both sides.
+	JoinFullOuter
+)
+
+// MergeJoin joins left and right sorted in ascending order of their keys without building a hash index.
+// Matching pairs are projected like in InnerJoin; unmatched elements are projected with a nil
+// counterpart if the kind keeps them. The result is in ascending order of the keys.
+// The result is undefined if either side is not sorted.
+func MergeJoin[L ~[]T, R ~[]U, T, U any, K constraints.Ordered, V any](left L, right R, leftKey func(T) K, rightKey func(U) K, kind JoinKind, project func(*T, *U) V) []V {
+	var (
+		keepLeft  = kind == JoinLeft || kind == JoinFullOuter
+		keepRight = kind == JoinRight || kind == JoinFullOuter
+		result    []V
+		i, j      int
+	)
+
+	for i < len(left) || j < len(right) {
+		switch {
+		case j == len(right) || i < len(left) && leftKey(left[i]) < rightKey(right[j]):
+			if keepLeft {
+				var l = left[i]
+				result = append(result, project(&l, nil))
+			}
+
+			i++
+		case i == len(left) || rightKey(right[j]) < leftKey(left[i]):
+			if keepRight {
+				var r = right[j]
+				result = append(result, project(nil, &r))
+			}
+
+			j++
+		default:
+			var (
+				key      = leftKey(left[i])
+				leftEnd  = i + 1
+				rightEnd = j + 1
+			)
+
+			for leftEnd < len(left) && leftKey(left[leftEnd]) == key {
+				leftEnd++
+			}
+
+			for rightEnd < len(right) && rightKey(right[rightEnd]) == key {
+				rightEnd++
+			}
+
+			for _, l := range left[i:leftEnd] {
+				for _, r := range right[j:rightEnd] {
+					var l, r = l, r
+					result = append(result, project(&l, &r))
+				}
+			}
+
+			i, j = leftEnd, rightEnd
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,159 @@
+package collection_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type joinCustomer struct {
+	ID   int
+	Name string
+}
+
+type joinOrder struct {
+	ID         string
+	CustomerID int
+}
+
+var (
+	joinCustomers = []joinCustomer{{1, "alice"}, {2, "bob"}, {3, "carol"}, {2, "bobby"}}
+	joinOrders    = []joinOrder{{"o1", 2}, {"o2", 1}, {"o3", 4}, {"o4", 2}}
+	customerID    = func(c joinCustomer) int { return c.ID }
+	orderCustomer = func(o joinOrder) int { return o.CustomerID }
+)
+
+func optionalName(c *joinCustomer) string {
+	if c == nil {
+		return "-"
+	}
+
+	return c.Name
+}
+
+func optionalOrder(o *joinOrder) string {
+	if o == nil {
+		return "-"
+	}
+
+	return o.ID
+}
+
+func TestHashJoins(t *testing.T) {
+	cases := []struct {
+		name string
+		join func() []string
+		want []string
+	}{
+		{
+			name: "inner",
+			join: func() []string {
+				return collection.InnerJoin(joinCustomers, joinOrders, customerID, orderCustomer, func(c joinCustomer, o joinOrder) string {
+					return c.Name + ":" + o.ID
+				})
+			},
+			want: []string{"alice:o2", "bob:o1", "bob:o4", "bobby:o1", "bobby:o4"},
+		},
+		{
+			name: "left",
+			join: func() []string {
+				return collection.LeftJoin(joinCustomers, joinOrders, customerID, orderCustomer, func(c joinCustomer, o *joinOrder) string {
+					return c.Name + ":" + optionalOrder(o)
+				})
+			},
+			want: []string{"alice:o2", "bob:o1", "bob:o4", "carol:-", "bobby:o1", "bobby:o4"},
+		},
+		{
+			name: "right",
+			join: func() []string {
+				return collection.RightJoin(joinCustomers, joinOrders, customerID, orderCustomer, func(c *joinCustomer, o joinOrder) string {
+					return optionalName(c) + ":" + o.ID
+				})
+			},
+			want: []string{"bob:o1", "bobby:o1", "alice:o2", "-:o3", "bob:o4", "bobby:o4"},
+		},
+		{
+			name: "full outer",
+			join: func() []string {
+				return collection.FullOuterJoin(joinCustomers, joinOrders, customerID, orderCustomer, func(c *joinCustomer, o *joinOrder) string {
+					return optionalName(c) + ":" + optionalOrder(o)
+				})
+			},
+			want: []string{"alice:o2", "bob:o1", "bob:o4", "carol:-", "bobby:o1", "bobby:o4", "-:o3"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.join(); !slices.Equal(got, tc.want) {
+				t.Errorf("join = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSemiAndAntiJoin(t *testing.T) {
+	semi := collection.SemiJoin(joinCustomers, joinOrders, customerID, orderCustomer)
+	if want := []joinCustomer{{1, "alice"}, {2, "bob"}, {2, "bobby"}}; !slices.Equal(semi, want) {
+		t.Errorf("SemiJoin() = %v; want %v", semi, want)
+	}
+
+	anti := collection.AntiJoin(joinOrders, joinCustomers, orderCustomer, customerID)
+	if want := []joinOrder{{"o3", 4}}; !slices.Equal(anti, want) {
+		t.Errorf("AntiJoin() = %v; want %v", anti, want)
+	}
+}
+
+func TestMergeJoin(t *testing.T) {
+	var (
+		left  = []joinCustomer{{1, "alice"}, {2, "bob"}, {2, "bobby"}, {3, "carol"}}
+		right = []joinOrder{{"o2", 1}, {"o1", 2}, {"o4", 2}, {"o3", 4}}
+	)
+
+	cases := []struct {
+		kind collection.JoinKind
+		want []string
+	}{
+		{kind: collection.JoinInner, want: []string{"alice:o2", "bob:o1", "bob:o4", "bobby:o1", "bobby:o4"}},
+		{kind: collection.JoinLeft, want: []string{"alice:o2", "bob:o1", "bob:o4", "bobby:o1", "bobby:o4", "carol:-"}},
+		{kind: collection.JoinRight, want: []string{"alice:o2", "bob:o1", "bob:o4", "bobby:o1", "bobby:o4", "-:o3"}},
+		{kind: collection.JoinFullOuter, want: []string{"alice:o2", "bob:o1", "bob:o4", "bobby:o1", "bobby:o4", "carol:-", "-:o3"}},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.kind), func(t *testing.T) {
+			got := collection.MergeJoin(left, right, customerID, orderCustomer, tc.kind, func(c *joinCustomer, o *joinOrder) string {
+				return optionalName(c) + ":" + optionalOrder(o)
+			})
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("MergeJoin() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// ExampleInnerJoin: Example function demonstrating the use of the InnerJoin function.
+func ExampleInnerJoin() {
+	type customer struct {
+		ID   int
+		Name string
+	}
+
+	type order struct {
+		ID         string
+		CustomerID int
+	}
+
+	customers := []customer{{1, "alice"}, {2, "bob"}}
+	orders := []order{{"o1", 2}, {"o2", 1}, {"o3", 2}}
+
+	result := collection.InnerJoin(orders, customers,
+		func(o order) int { return o.CustomerID },
+		func(c customer) int { return c.ID },
+		func(o order, c customer) string { return o.ID + " by " + c.Name },
+	)
+
+	fmt.Println(result)
+	// Output: [o1 by bob o2 by alice o3 by bob]
+}
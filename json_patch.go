@@ -0,0 +1,362 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOp is the operation of a JSON Patch (RFC 6902) operation.
+type JSONPatchOp string
+
+const (
+	// JSONPatchAdd adds a map entry or inserts an array element.
+	JSONPatchAdd JSONPatchOp = "add"
+	// JSONPatchRemove removes a map entry or an array element.
+	JSONPatchRemove JSONPatchOp = "remove"
+	// JSONPatchReplace replaces an existing value.
+	JSONPatchReplace JSONPatchOp = "replace"
+	// JSONPatchMove removes the value at From and adds it at Path.
+	JSONPatchMove JSONPatchOp = "move"
+	// JSONPatchCopy adds a copy of the value at From at Path.
+	JSONPatchCopy JSONPatchOp = "copy"
+	// JSONPatchTest checks that the value at Path equals Value, comparing numbers by their value.
+	JSONPatchTest JSONPatchOp = "test"
+)
+
+// JSONPatchOperation is an operation of a JSON Patch. Path and From are JSON Pointers.
+type JSONPatchOperation struct {
+	Op    JSONPatchOp `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value any         `json:"value"`
+}
+
+// MarshalJSON encodes the operation with the members its kind requires, so null values are kept.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case JSONPatchAdd, JSONPatchReplace, JSONPatchTest:
+		return json.Marshal(struct {
+			Op    JSONPatchOp `json:"op"`
+			Path  string      `json:"path"`
+			Value any         `json:"value"`
+		}{o.Op, o.Path, o.Value})
+	case JSONPatchMove, JSONPatchCopy:
+		return json.Marshal(struct {
+			Op   JSONPatchOp `json:"op"`
+			From string      `json:"from"`
+			Path string      `json:"path"`
+		}{o.Op, o.From, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op   JSONPatchOp `json:"op"`
+		Path string      `json:"path"`
+	}{o.Op, o.Path})
+}
+
+// JSONPatchDiff returns the JSON Patch turning the from document into the to document.
+// Maps are compared entry by entry, removals first, in order of keys. Arrays are compared
+// with Diff, changed elements being replaced, or patched recursively if both are maps.
+func JSONPatchDiff(from, to map[string]any) []JSONPatchOperation {
+	return diffDocuments(nil, from, to, nil)
+}
+
+// JSONPatchApply applies the JSON Patch to a copy of the document and returns it. The patch is atomic:
+// if an operation fails, including a failed test operation, JSONPatchApply returns an error wrapping
+// ErrPatchConflict and the document is left unchanged. The patched document shares no values with
+// the document or the patch.
+func JSONPatchApply(doc map[string]any, patch []JSONPatchOperation) (map[string]any, error) {
+	var result = copyDocument(doc).(map[string]any)
+
+	for i, operation := range patch {
+		var err error
+		if result, err = applyJSONPatchOperation(result, operation); err != nil {
+			return nil, fmt.Errorf("%w: operation %d (%s %s): %w", ErrPatchConflict, i, operation.Op, operation.Path, err)
+		}
+	}
+
+	return result, nil
+}
+
+// MergePatchDiff returns the JSON Merge Patch (RFC 7386) turning the from document into the to document.
+// Removed entries are nil and maps are patched recursively; other changed values, including arrays,
+// are replaced. Merge patches can't set values to null, so entries changed to nil are removed.
+func MergePatchDiff(from, to map[string]any) map[string]any {
+	var (
+		diff   = MapDiffFunc(from, to, documentEqual)
+		result = make(map[string]any, len(diff.Added)+len(diff.Removed)+len(diff.Changed))
+	)
+
+	for key := range diff.Removed {
+		result[key] = nil
+	}
+
+	for key, value := range diff.Added {
+		result[key] = copyDocument(value)
+	}
+
+	for key, change := range diff.Changed {
+		old, okOld := change.Old.(map[string]any)
+		value, okNew := change.New.(map[string]any)
+		if okOld && okNew {
+			result[key] = MergePatchDiff(old, value)
+			continue
+		}
+
+		result[key] = copyDocument(change.New)
+	}
+
+	return result
+}
+
+// MergePatchApply applies the JSON Merge Patch to the document and returns the patched document.
+// The document is left unchanged; the result shares no values with the patch.
+func MergePatchApply(doc, patch map[string]any) map[string]any {
+	var result = MapClone(doc)
+	if result == nil {
+		result = make(map[string]any, len(patch))
+	}
+
+	for key, value := range patch {
+		switch value := value.(type) {
+		case nil:
+			delete(result, key)
+		case map[string]any:
+			var target, _ = result[key].(map[string]any)
+			result[key] = MergePatchApply(target, value)
+		default:
+			result[key] = copyDocument(value)
+		}
+	}
+
+	return result
+}
+
+func diffDocuments(segments []string, from, to map[string]any, result []JSONPatchOperation) []JSONPatchOperation {
+	var diff = MapDiffFunc(from, to, documentEqual)
+
+	for _, key := range sortedKeys(diff.Removed) {
+		result = append(result, JSONPatchOperation{Op: JSONPatchRemove, Path: formatPath(appendSegment(segments, key))})
+	}
+
+	for _, key := range sortedKeys(diff.Changed) {
+		result = diffValues(appendSegment(segments, key), diff.Changed[key], result)
+	}
+
+	for _, key := range sortedKeys(diff.Added) {
+		result = append(result, JSONPatchOperation{Op: JSONPatchAdd, Path: formatPath(appendSegment(segments, key)), Value: copyDocument(diff.Added[key])})
+	}
+
+	return result
+}
+
+// diffValues appends the operations turning the old value at the path into the new one.
+func diffValues(segments []string, change Change[any], result []JSONPatchOperation) []JSONPatchOperation {
+	switch old := change.Old.(type) {
+	case map[string]any:
+		if value, ok := change.New.(map[string]any); ok {
+			return diffDocuments(segments, old, value, result)
+		}
+	case []any:
+		if value, ok := change.New.([]any); ok {
+			return diffArrays(segments, old, value, result)
+		}
+	}
+
+	return append(result, JSONPatchOperation{Op: JSONPatchReplace, Path: formatPath(segments), Value: copyDocument(change.New)})
+}
+
+// diffArrays turns every run of deleted and inserted elements of the edit script into replacements
+// of the leading elements of the run, followed by removals or additions of the remaining ones.
+func diffArrays(segments []string, from, to []any, result []JSONPatchOperation) []JSONPatchOperation {
+	var (
+		edits = DiffFunc(from, to, documentEqual)
+		index int
+	)
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			index, i = index+1, i+1
+			continue
+		}
+
+		var deleted, inserted []any
+		for ; i < len(edits) && edits[i].Op != EditEqual; i++ {
+			if edits[i].Op == EditDelete {
+				deleted = append(deleted, edits[i].Value)
+			} else {
+				inserted = append(inserted, edits[i].Value)
+			}
+		}
+
+		var replaced = Min(len(deleted), len(inserted))
+		for j := 0; j < replaced; j++ {
+			result = diffValues(appendSegment(segments, strconv.Itoa(index+j)), Change[any]{Old: deleted[j], New: inserted[j]}, result)
+		}
+
+		for j := replaced; j < len(deleted); j++ {
+			result = append(result, JSONPatchOperation{Op: JSONPatchRemove, Path: formatPath(appendSegment(segments, strconv.Itoa(index+replaced)))})
+		}
+
+		for j := replaced; j < len(inserted); j++ {
+			result = append(result, JSONPatchOperation{Op: JSONPatchAdd, Path: formatPath(appendSegment(segments, strconv.Itoa(index+j))), Value: copyDocument(inserted[j])})
+		}
+
+		index += len(inserted)
+	}
+
+	return result
+}
+
+func applyJSONPatchOperation(doc map[string]any, operation JSONPatchOperation) (map[string]any, error) {
+	for _, path := range []string{operation.Path, operation.From} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("%w: %q is not a JSON Pointer", ErrInvalidPath, path)
+		}
+	}
+
+	switch operation.Op {
+	case JSONPatchAdd:
+		return addDocumentValue(doc, operation.Path, copyDocument(operation.Value))
+	case JSONPatchRemove:
+		return doc, MapDeletePath(doc, operation.Path)
+	case JSONPatchReplace:
+		if _, err := MapGetPath(doc, operation.Path); err != nil {
+			return nil, err
+		}
+
+		if operation.Path == "" {
+			return addDocumentValue(doc, operation.Path, copyDocument(operation.Value))
+		}
+
+		return doc, pathSetter{}.set(doc, operation.Path, copyDocument(operation.Value))
+	case JSONPatchMove:
+		var value, err = MapGetPath(doc, operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Path == operation.From {
+			return doc, nil
+		}
+
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, fmt.Errorf("%w: can't move %s into itself", ErrInvalidPath, operation.From)
+		}
+
+		if err := MapDeletePath(doc, operation.From); err != nil {
+			return nil, err
+		}
+
+		return addDocumentValue(doc, operation.Path, value)
+	case JSONPatchCopy:
+		var value, err = MapGetPath(doc, operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return addDocumentValue(doc, operation.Path, copyDocument(value))
+	case JSONPatchTest:
+		var value, err = MapGetPath(doc, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !documentEqual(value, operation.Value) {
+			return nil, fmt.Errorf("test failed: %v (%T) != %v (%T)", value, value, operation.Value, operation.Value)
+		}
+
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operation %q", operation.Op)
+}
+
+// addDocumentValue adds the value at the path, inserting array elements. The parent of the value must exist.
+// The empty path replaces the document.
+func addDocumentValue(doc map[string]any, path string, value any) (map[string]any, error) {
+	if path != "" {
+		return doc, pathSetter{insert: true}.set(doc, path, value)
+	}
+
+	result, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: document replaced by %T", ErrPathType, value)
+	}
+
+	return result, nil
+}
+
+// copyDocument returns a deep copy of the maps and arrays of the document value.
+func copyDocument(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		var result = make(map[string]any, len(v))
+		for key, child := range v {
+			result[key] = copyDocument(child)
+		}
+
+		return result
+	case []any:
+		return TransformBy(v, copyDocument)
+	}
+
+	return value
+}
+
+// documentEqual compares document values, numbers by their value regardless of their type.
+func documentEqual(l, r any) bool {
+	if x, ok := documentNumber(l); ok {
+		y, ok := documentNumber(r)
+		return ok && x == y
+	}
+
+	switch l := l.(type) {
+	case map[string]any:
+		var r, ok = r.(map[string]any)
+		return ok && len(l) == len(r) && All(MapKeys(l), func(key string) bool {
+			var value, ok = r[key]
+			return ok && documentEqual(l[key], value)
+		})
+	case []any:
+		var r, ok = r.([]any)
+		return ok && len(l) == len(r) && EqualFunc(l, r, documentEqual)
+	}
+
+	return reflect.DeepEqual(l, r)
+}
+
+// documentNumber returns the value of integers, floats and json.Number values as a float64,
+// the type encoding/json decodes numbers to.
+func documentNumber(value any) (float64, bool) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	if number, ok := value.(json.Number); ok {
+		var f, err = number.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func appendSegment(segments []string, segment string) []string {
+	return append(segments[:len(segments):len(segments)], segment)
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	var keys = MapKeys(m)
+	sort.Strings(keys)
+
+	return keys
+}
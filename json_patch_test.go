@@ -0,0 +1,274 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestJSONPatchDiff(t *testing.T) {
+	testCases := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "equal",
+			from: `{"a": [1, {"b": null}]}`,
+			to:   `{"a": [1, {"b": null}]}`,
+			want: `null`,
+		},
+		{
+			name: "entries",
+			from: `{"keep": 1, "old": true, "nested": {"x": 1, "y": 2}, "kind": {"a": 1}}`,
+			to:   `{"keep": 1, "new": null, "nested": {"x": 3, "y": 2}, "kind": [1]}`,
+			want: `[
+				{"op": "remove", "path": "/old"},
+				{"op": "replace", "path": "/kind", "value": [1]},
+				{"op": "replace", "path": "/nested/x", "value": 3},
+				{"op": "add", "path": "/new", "value": null}
+			]`,
+		},
+		{
+			name: "arrays",
+			from: `{"tags": ["a", "b", "c", "d"], "users": [{"name": "ann", "age": 30}]}`,
+			to:   `{"tags": ["a", "x", "d", "e"], "users": [{"name": "ann", "age": 31}]}`,
+			want: `[
+				{"op": "replace", "path": "/tags/1", "value": "x"},
+				{"op": "remove", "path": "/tags/2"},
+				{"op": "add", "path": "/tags/3", "value": "e"},
+				{"op": "replace", "path": "/users/0/age", "value": 31}
+			]`,
+		},
+		{
+			name: "escaped keys",
+			from: `{"a/b": {"m~n": 1}}`,
+			to:   `{"a/b": {"m~n": 2}}`,
+			want: `[{"op": "replace", "path": "/a~1b/m~0n", "value": 2}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := decodeDocument(t, tc.from), decodeDocument(t, tc.to)
+
+			patch := collection.JSONPatchDiff(from, to)
+
+			var got, want any
+			data, _ := json.Marshal(patch)
+			_ = json.Unmarshal(data, &got)
+			_ = json.Unmarshal([]byte(tc.want), &want)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("JSONPatchDiff() = %s; want %s", data, tc.want)
+			}
+
+			patched, err := collection.JSONPatchApply(from, patch)
+			if err != nil || !reflect.DeepEqual(patched, to) {
+				t.Errorf("JSONPatchApply(JSONPatchDiff()) = %v, %v; want %v", patched, err, to)
+			}
+		})
+	}
+}
+
+func TestJSONPatchApply(t *testing.T) {
+	var doc = decodeDocument(t, `{"foo": ["bar", "baz"], "obj": {"a": 1}, "n": null}`)
+
+	testCases := []struct {
+		name  string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "add inserts",
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}, {"op": "add", "path": "/foo/-", "value": "end"}]`,
+			want:  `{"foo": ["bar", "qux", "baz", "end"], "obj": {"a": 1}, "n": null}`,
+		},
+		{
+			name:  "move and copy",
+			patch: `[{"op": "move", "from": "/obj/a", "path": "/b"}, {"op": "copy", "from": "/foo", "path": "/obj/foo"}]`,
+			want:  `{"foo": ["bar", "baz"], "obj": {"foo": ["bar", "baz"]}, "b": 1, "n": null}`,
+		},
+		{
+			name:  "test and replace",
+			patch: `[{"op": "test", "path": "/n", "value": null}, {"op": "replace", "path": "/foo/0", "value": {"x": 1}}, {"op": "remove", "path": "/obj"}]`,
+			want:  `{"foo": [{"x": 1}, "baz"], "n": null}`,
+		},
+		{
+			name:  "replace document",
+			patch: `[{"op": "replace", "path": "", "value": {"a": 1}}]`,
+			want:  `{"a": 1}`,
+		},
+		{
+			name:  "test numbers by value",
+			patch: `[{"op": "test", "path": "/obj/a", "value": 1}]`,
+			want:  `{"foo": ["bar", "baz"], "obj": {"a": 1}, "n": null}`,
+		},
+		{
+			name:  "add without parent",
+			patch: `[{"op": "add", "path": "/a/b/c", "value": 1}]`,
+			err:   collection.ErrPathNotFound,
+		},
+		{
+			name:  "copy without parent",
+			patch: `[{"op": "copy", "from": "/foo", "path": "/missing/foo"}]`,
+			err:   collection.ErrPathNotFound,
+		},
+		{
+			name:  "add below null",
+			patch: `[{"op": "add", "path": "/n/a", "value": 1}]`,
+			err:   collection.ErrPathType,
+		},
+		{
+			name:  "failed test",
+			patch: `[{"op": "remove", "path": "/obj"}, {"op": "test", "path": "/foo/0", "value": "baz"}]`,
+			err:   collection.ErrPatchConflict,
+		},
+		{
+			name:  "replace missing",
+			patch: `[{"op": "replace", "path": "/missing", "value": 1}]`,
+			err:   collection.ErrPathNotFound,
+		},
+		{
+			name:  "move into itself",
+			patch: `[{"op": "move", "from": "/obj", "path": "/obj/inner"}]`,
+			err:   collection.ErrInvalidPath,
+		},
+		{
+			name:  "dotted path",
+			patch: `[{"op": "remove", "path": "obj.a"}]`,
+			err:   collection.ErrInvalidPath,
+		},
+		{
+			name:  "unknown operation",
+			patch: `[{"op": "rename", "path": "/obj"}]`,
+			err:   collection.ErrPatchConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var patch []collection.JSONPatchOperation
+			if err := json.Unmarshal([]byte(tc.patch), &patch); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			before := collection.MapFlatten(doc)
+
+			got, err := collection.JSONPatchApply(doc, patch)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("JSONPatchApply() error = %v; want %v", err, tc.err)
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(got, decodeDocument(t, tc.want)) {
+				t.Errorf("JSONPatchApply() = %v; want %s", got, tc.want)
+			}
+
+			if !reflect.DeepEqual(collection.MapFlatten(doc), before) {
+				t.Errorf("JSONPatchApply() modified the document")
+			}
+		})
+	}
+}
+
+func TestJSONPatchApplyGoValues(t *testing.T) {
+	var doc = map[string]any{"count": 2.0, "tags": []any{"a"}}
+
+	patch := []collection.JSONPatchOperation{
+		{Op: collection.JSONPatchTest, Path: "/count", Value: 2},
+		{Op: collection.JSONPatchTest, Path: "/tags", Value: []any{"a"}},
+		{Op: collection.JSONPatchReplace, Path: "/count", Value: 3},
+	}
+
+	got, err := collection.JSONPatchApply(doc, patch)
+	if want := map[string]any{"count": 3, "tags": []any{"a"}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONPatchApply() = %v, %v; want %v", got, err, want)
+	}
+
+	_, err = collection.JSONPatchApply(doc, []collection.JSONPatchOperation{{Op: collection.JSONPatchTest, Path: "/count", Value: "2"}})
+	if err == nil || !strings.Contains(err.Error(), "2 (float64) != 2 (string)") {
+		t.Errorf("JSONPatchApply() error = %v; want a failed test showing the types", err)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	testCases := []struct {
+		name  string
+		from  string
+		to    string
+		patch string
+	}{
+		{
+			name:  "rfc example",
+			from:  `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "text"}`,
+			to:    `{"title": "Hello!", "author": {"givenName": "John"}, "tags": ["example"], "content": "text", "phoneNumber": "+01-123-456-7890"}`,
+			patch: `{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`,
+		},
+		{
+			name:  "equal",
+			from:  `{"a": {"b": [1, 2]}}`,
+			to:    `{"a": {"b": [1, 2]}}`,
+			patch: `{}`,
+		},
+		{
+			name:  "scalar to object",
+			from:  `{"a": 1, "b": {"c": 1}}`,
+			to:    `{"a": {"x": 1}}`,
+			patch: `{"a": {"x": 1}, "b": null}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := decodeDocument(t, tc.from), decodeDocument(t, tc.to)
+
+			patch := collection.MergePatchDiff(from, to)
+			if want := decodeDocument(t, tc.patch); !reflect.DeepEqual(patch, want) {
+				t.Errorf("MergePatchDiff() = %v; want %v", patch, want)
+			}
+
+			if got := collection.MergePatchApply(from, patch); !reflect.DeepEqual(got, to) {
+				t.Errorf("MergePatchApply() = %v; want %v", got, to)
+			}
+
+			if !reflect.DeepEqual(from, decodeDocument(t, tc.from)) {
+				t.Errorf("MergePatchApply() modified the document")
+			}
+		})
+	}
+}
+
+// ExampleJSONPatchDiff: Example function demonstrating the use of the JSONPatchDiff function.
+func ExampleJSONPatchDiff() {
+	var before, after map[string]any
+	_ = json.Unmarshal([]byte(`{"replicas": 2, "image": "app:1.0", "ports": [80]}`), &before)
+	_ = json.Unmarshal([]byte(`{"replicas": 3, "image": "app:1.0", "ports": [80, 443]}`), &after)
+
+	patch := collection.JSONPatchDiff(before, after)
+	data, _ := json.Marshal(patch)
+	fmt.Println(string(data))
+
+	patched, err := collection.JSONPatchApply(before, patch)
+	fmt.Println(reflect.DeepEqual(patched, after), err)
+	// Output:
+	// [{"op":"add","path":"/ports/1","value":443},{"op":"replace","path":"/replicas","value":3}]
+	// true <nil>
+}
+
+// ExampleMergePatchDiff: Example function demonstrating the use of the MergePatchDiff function.
+func ExampleMergePatchDiff() {
+	var before = map[string]any{"replicas": 2.0, "debug": true, "labels": map[string]any{"team": "core"}}
+	var after = map[string]any{"replicas": 3.0, "labels": map[string]any{"team": "core", "tier": "web"}}
+
+	data, _ := json.Marshal(collection.MergePatchDiff(before, after))
+	fmt.Println(string(data))
+	// Output:
+	// {"debug":null,"labels":{"tier":"web"},"replicas":3}
+}
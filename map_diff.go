@@ -0,0 +1,143 @@
+package collection
+
+// Change is the old and new value of a changed map entry.
+type Change[V any] struct {
+	Old V
+	New V
+}
+
+// MapDifference describes how the entries of a map differ from those of another one.
+type MapDifference[K comparable, V any] struct {
+	Added     map[K]V
+	Removed   map[K]V
+	Changed   map[K]Change[V]
+	Unchanged map[K]V
+}
+
+// Empty reports whether the maps have no added, removed or changed entries.
+func (d MapDifference[K, V]) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// MapDiff compares the entries of the to map with those of the from map.
+func MapDiff[M ~map[K]V, K, V comparable](from, to M) MapDifference[K, V] {
+	return MapDiffFunc(from, to, func(l, r V) bool { return l == r })
+}
+
+// MapDiffFunc is like MapDiff, but compares the values using the equal function provided.
+func MapDiffFunc[M ~map[K]V, K comparable, V any](from, to M, equal func(l, r V) bool) MapDifference[K, V] {
+	var result = MapDifference[K, V]{
+		Added:     make(map[K]V),
+		Removed:   make(map[K]V),
+		Changed:   make(map[K]Change[V]),
+		Unchanged: make(map[K]V),
+	}
+
+	for key, old := range from {
+		value, ok := to[key]
+		switch {
+		case !ok:
+			result.Removed[key] = old
+		case equal(old, value):
+			result.Unchanged[key] = value
+		default:
+			result.Changed[key] = Change[V]{Old: old, New: value}
+		}
+	}
+
+	for key, value := range to {
+		if _, ok := from[key]; !ok {
+			result.Added[key] = value
+		}
+	}
+
+	return result
+}
+
+// MapDiffApply returns a copy of the source with the difference applied: removed entries
+// are deleted, and added and changed entries are set to their new values.
+func MapDiffApply[M ~map[K]V, K comparable, V any](source M, diff MapDifference[K, V]) M {
+	var result = MapClone(source)
+	if result == nil {
+		result = make(M, len(diff.Added))
+	}
+
+	for key := range diff.Removed {
+		delete(result, key)
+	}
+
+	for key, value := range diff.Added {
+		result[key] = value
+	}
+
+	for key, change := range diff.Changed {
+		result[key] = change.New
+	}
+
+	return result
+}
+
+// MergeConflict is an entry changed differently by both sides of a three-way merge.
+// Nil values denote an entry missing from a map.
+type MergeConflict[K comparable, V any] struct {
+	Key    K
+	Base   *V
+	Ours   *V
+	Theirs *V
+}
+
+// MapMerge3 merges the changes ours and theirs made to the base map. An entry changed on one
+// side only takes the changed value or is deleted; an entry changed the same way on both sides
+// takes that value. Entries changed differently on both sides are reported as conflicts,
+// in no particular order, and keep the value of ours in the merged map.
+func MapMerge3[M ~map[K]V, K, V comparable](base, ours, theirs M) (M, []MergeConflict[K, V]) {
+	return MapMerge3Func(base, ours, theirs, func(l, r V) bool { return l == r })
+}
+
+// MapMerge3Func is like MapMerge3, but compares the values using the equal function provided.
+func MapMerge3Func[M ~map[K]V, K comparable, V any](base, ours, theirs M, equal func(l, r V) bool) (M, []MergeConflict[K, V]) {
+	var (
+		result    = make(M, len(ours))
+		conflicts []MergeConflict[K, V]
+		keys      = make(map[K]struct{}, len(base))
+		same      = func(l, r *V) bool {
+			return l == nil && r == nil || l != nil && r != nil && equal(*l, *r)
+		}
+	)
+
+	for _, m := range []M{base, ours, theirs} {
+		for key := range m {
+			keys[key] = struct{}{}
+		}
+	}
+
+	for key := range keys {
+		var (
+			b, o, t = mapEntry(base, key), mapEntry(ours, key), mapEntry(theirs, key)
+			merged  = o
+		)
+
+		switch {
+		case same(o, t), same(b, t):
+		case same(b, o):
+			merged = t
+		default:
+			conflicts = append(conflicts, MergeConflict[K, V]{Key: key, Base: b, Ours: o, Theirs: t})
+		}
+
+		if merged != nil {
+			result[key] = *merged
+		}
+	}
+
+	return result, conflicts
+}
+
+// mapEntry returns a pointer to a copy of the value stored for the key or nil if it is missing.
+func mapEntry[M ~map[K]V, K comparable, V any](m M, key K) *V {
+	if value, ok := m[key]; ok {
+		return &value
+	}
+
+	return nil
+}
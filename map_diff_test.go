@@ -0,0 +1,85 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestMapDiff(t *testing.T) {
+	var (
+		desired = map[string]int{"web": 3, "worker": 2, "cron": 1}
+		actual  = map[string]int{"web": 2, "worker": 2, "legacy": 1}
+	)
+
+	diff := collection.MapDiff(actual, desired)
+
+	want := collection.MapDifference[string, int]{
+		Added:     map[string]int{"cron": 1},
+		Removed:   map[string]int{"legacy": 1},
+		Changed:   map[string]collection.Change[int]{"web": {Old: 2, New: 3}},
+		Unchanged: map[string]int{"worker": 2},
+	}
+
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("MapDiff() = %+v; want %+v", diff, want)
+	}
+
+	if got := collection.MapDiffApply(actual, diff); !reflect.DeepEqual(got, desired) {
+		t.Errorf("MapDiffApply() = %v; want %v", got, desired)
+	}
+
+	if _, ok := actual["cron"]; ok {
+		t.Errorf("MapDiffApply() modified the source")
+	}
+
+	if !collection.MapDiff(desired, desired).Empty() || diff.Empty() {
+		t.Errorf("Empty() is wrong")
+	}
+}
+
+func TestMapDiffFunc(t *testing.T) {
+	diff := collection.MapDiffFunc(
+		map[int]string{1: "Alice", 2: "bob"},
+		map[int]string{1: "alice", 2: "Robert"},
+		strings.EqualFold,
+	)
+
+	if len(diff.Unchanged) != 1 || diff.Changed[2] != (collection.Change[string]{Old: "bob", New: "Robert"}) {
+		t.Errorf("MapDiffFunc() = %+v", diff)
+	}
+
+	if got := collection.MapDiffApply(map[int]string(nil), diff); !reflect.DeepEqual(got, map[int]string{2: "Robert"}) {
+		t.Errorf("MapDiffApply(nil) = %v", got)
+	}
+}
+
+func TestMapMerge3(t *testing.T) {
+	var (
+		base   = map[string]string{"host": "a", "port": "80", "user": "root", "mode": "dev", "tls": "off"}
+		ours   = map[string]string{"host": "b", "port": "80", "user": "admin", "tls": "on", "debug": "1"}
+		theirs = map[string]string{"host": "a", "port": "8080", "user": "guest", "mode": "prod", "tls": "on"}
+	)
+
+	merged, conflicts := collection.MapMerge3(base, ours, theirs)
+
+	want := map[string]string{"host": "b", "port": "8080", "user": "admin", "tls": "on", "debug": "1"}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("MapMerge3() = %v; want %v", merged, want)
+	}
+
+	byKey := collection.SliceToMap(conflicts, func(c collection.MergeConflict[string, string]) string { return c.Key })
+	if len(conflicts) != 2 {
+		t.Fatalf("MapMerge3() conflicts = %v; want user and mode", conflicts)
+	}
+
+	if user := byKey["user"]; *user.Base != "root" || *user.Ours != "admin" || *user.Theirs != "guest" {
+		t.Errorf("user conflict = %v, %v, %v", *user.Base, *user.Ours, *user.Theirs)
+	}
+
+	if mode := byKey["mode"]; mode.Ours != nil || *mode.Theirs != "prod" {
+		t.Errorf("mode conflict = %v, %v", mode.Ours, *mode.Theirs)
+	}
+}
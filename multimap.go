@@ -0,0 +1,317 @@
+package collection
+
+// MultiMap maps each key to a group of values.
+type MultiMap[K, V comparable] interface {
+	// Put adds the value to the key group and reports whether the multimap changed.
+	Put(key K, value V) bool
+	// PutAll adds the values to the key group and reports whether the multimap changed.
+	PutAll(key K, values ...V) bool
+	// Remove removes a single value from the key group and reports whether it was present.
+	Remove(key K, value V) bool
+	// RemoveAll removes the key group and returns its values.
+	RemoveAll(key K) []V
+	// Get returns a copy of the values grouped under the key.
+	Get(key K) []V
+	// ContainsKey reports whether the key has at least one value.
+	ContainsKey(key K) bool
+	// ContainsEntry reports whether the value is grouped under the key.
+	ContainsEntry(key K, value V) bool
+	// KeyCount returns the number of distinct keys.
+	KeyCount() int
+	// ValueCount returns the number of values across all keys.
+	ValueCount() int
+	// Keys returns all keys in unspecified order.
+	Keys() []K
+	// Each calls do for each key-value entry.
+	Each(do func(key K, value V))
+	// ToMap returns a copy of the multimap as a map of value slices.
+	ToMap() map[K][]V
+	// Inverse returns a value to keys view of the multimap. The view shares storage with the multimap,
+	// so changes made through either of them are visible in both.
+	Inverse() MultiMap[V, K]
+}
+
+// ListMultiMap is a MultiMap that keeps values of a key in insertion order and allows duplicates.
+// Like BiMap, it indexes entries by value too, so its inverse view is kept up to date.
+type ListMultiMap[K, V comparable] struct {
+	m       map[K][]V
+	inverse map[V][]K
+	size    *int
+}
+
+// NewListMultiMap returns an empty ListMultiMap.
+func NewListMultiMap[K, V comparable]() *ListMultiMap[K, V] {
+	return &ListMultiMap[K, V]{m: make(map[K][]V), inverse: make(map[V][]K), size: new(int)}
+}
+
+// ListMultiMapFromGroups returns a ListMultiMap holding a copy of the groups, e.g. GroupBy output.
+func ListMultiMapFromGroups[M ~map[K]S, S ~[]V, K, V comparable](groups M) *ListMultiMap[K, V] {
+	var result = NewListMultiMap[K, V]()
+	for key, values := range groups {
+		result.PutAll(key, values...)
+	}
+
+	return result
+}
+
+// Put appends the value to the key group. It always returns true.
+func (m *ListMultiMap[K, V]) Put(key K, value V) bool {
+	m.m[key] = append(m.m[key], value)
+	m.inverse[value] = append(m.inverse[value], key)
+	*m.size++
+	return true
+}
+
+// PutAll appends the values to the key group. It returns false if there are no values.
+func (m *ListMultiMap[K, V]) PutAll(key K, values ...V) bool {
+	for _, value := range values {
+		m.Put(key, value)
+	}
+
+	return len(values) > 0
+}
+
+// Remove removes the first occurrence of the value from the key group.
+func (m *ListMultiMap[K, V]) Remove(key K, value V) bool {
+	if !removeListEntry(m.m, key, value) {
+		return false
+	}
+
+	removeListEntry(m.inverse, value, key)
+	*m.size--
+	return true
+}
+
+// RemoveAll removes the key group and returns its values in insertion order.
+func (m *ListMultiMap[K, V]) RemoveAll(key K) []V {
+	var values = m.m[key]
+	for _, value := range values {
+		removeListEntry(m.inverse, value, key)
+	}
+
+	delete(m.m, key)
+	*m.size -= len(values)
+	return values
+}
+
+// Get returns a copy of the values grouped under the key in insertion order.
+func (m *ListMultiMap[K, V]) Get(key K) []V {
+	return Clone(m.m[key])
+}
+
+// ContainsKey reports whether the key has at least one value.
+func (m *ListMultiMap[K, V]) ContainsKey(key K) bool {
+	return MapContains(m.m, key)
+}
+
+// ContainsEntry reports whether the value is grouped under the key.
+func (m *ListMultiMap[K, V]) ContainsEntry(key K, value V) bool {
+	return Contains(m.m[key], value)
+}
+
+// KeyCount returns the number of distinct keys.
+func (m *ListMultiMap[K, V]) KeyCount() int {
+	return len(m.m)
+}
+
+// ValueCount returns the number of values across all keys, counting duplicates.
+func (m *ListMultiMap[K, V]) ValueCount() int {
+	return *m.size
+}
+
+// Keys returns all keys in unspecified order.
+func (m *ListMultiMap[K, V]) Keys() []K {
+	return MapKeys(m.m)
+}
+
+// Each calls do for each key-value entry, visiting the values of a key in insertion order.
+func (m *ListMultiMap[K, V]) Each(do func(key K, value V)) {
+	for key, values := range m.m {
+		for _, value := range values {
+			do(key, value)
+		}
+	}
+}
+
+// ToMap returns a copy of the multimap as a map of value slices.
+func (m *ListMultiMap[K, V]) ToMap() map[K][]V {
+	return MapTransformBy(m.m, Clone[[]V])
+}
+
+// Inverse returns a ListMultiMap view grouping the keys by value. The keys of a value
+// are in insertion order of their entries, and a key grouped twice under a value appears twice.
+func (m *ListMultiMap[K, V]) Inverse() MultiMap[V, K] {
+	return &ListMultiMap[V, K]{m: m.inverse, inverse: m.m, size: m.size}
+}
+
+// removeListEntry removes the first occurrence of the value from the key group.
+func removeListEntry[K, V comparable](m map[K][]V, key K, value V) bool {
+	var values = m[key]
+	for i, v := range values {
+		if v != value {
+			continue
+		}
+
+		if len(values) == 1 {
+			delete(m, key)
+		} else {
+			m[key] = append(values[:i:i], values[i+1:]...)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// SetMultiMap is a MultiMap that keeps distinct values of a key in unspecified order.
+// Like BiMap, it indexes entries by value too, so its inverse view is kept up to date.
+type SetMultiMap[K, V comparable] struct {
+	m       map[K]map[V]struct{}
+	inverse map[V]map[K]struct{}
+	size    *int
+}
+
+// NewSetMultiMap returns an empty SetMultiMap.
+func NewSetMultiMap[K, V comparable]() *SetMultiMap[K, V] {
+	return &SetMultiMap[K, V]{m: make(map[K]map[V]struct{}), inverse: make(map[V]map[K]struct{}), size: new(int)}
+}
+
+// SetMultiMapFromGroups returns a SetMultiMap holding the distinct values of the groups, e.g. GroupBy output.
+func SetMultiMapFromGroups[M ~map[K]S, S ~[]V, K, V comparable](groups M) *SetMultiMap[K, V] {
+	var result = NewSetMultiMap[K, V]()
+	for key, values := range groups {
+		result.PutAll(key, values...)
+	}
+
+	return result
+}
+
+// Put adds the value to the key group, it returns false if the value is already present.
+func (m *SetMultiMap[K, V]) Put(key K, value V) bool {
+	if !addSetEntry(m.m, key, value) {
+		return false
+	}
+
+	addSetEntry(m.inverse, value, key)
+	*m.size++
+	return true
+}
+
+// PutAll adds the values to the key group and reports whether any of them was not present.
+func (m *SetMultiMap[K, V]) PutAll(key K, values ...V) bool {
+	var changed bool
+	for _, value := range values {
+		if m.Put(key, value) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// Remove removes the value from the key group and reports whether it was present.
+func (m *SetMultiMap[K, V]) Remove(key K, value V) bool {
+	if !removeSetEntry(m.m, key, value) {
+		return false
+	}
+
+	removeSetEntry(m.inverse, value, key)
+	*m.size--
+	return true
+}
+
+// RemoveAll removes the key group and returns its values in unspecified order.
+func (m *SetMultiMap[K, V]) RemoveAll(key K) []V {
+	var values = m.Get(key)
+	for _, value := range values {
+		removeSetEntry(m.inverse, value, key)
+	}
+
+	delete(m.m, key)
+	*m.size -= len(values)
+	return values
+}
+
+// Get returns the values grouped under the key in unspecified order.
+func (m *SetMultiMap[K, V]) Get(key K) []V {
+	var set = m.m[key]
+	if len(set) == 0 {
+		return nil
+	}
+
+	return MapKeys(set)
+}
+
+// ContainsKey reports whether the key has at least one value.
+func (m *SetMultiMap[K, V]) ContainsKey(key K) bool {
+	return MapContains(m.m, key)
+}
+
+// ContainsEntry reports whether the value is grouped under the key.
+func (m *SetMultiMap[K, V]) ContainsEntry(key K, value V) bool {
+	return MapContains(m.m[key], value)
+}
+
+// KeyCount returns the number of distinct keys.
+func (m *SetMultiMap[K, V]) KeyCount() int {
+	return len(m.m)
+}
+
+// ValueCount returns the number of values across all keys.
+func (m *SetMultiMap[K, V]) ValueCount() int {
+	return *m.size
+}
+
+// Keys returns all keys in unspecified order.
+func (m *SetMultiMap[K, V]) Keys() []K {
+	return MapKeys(m.m)
+}
+
+// Each calls do for each key-value entry in unspecified order.
+func (m *SetMultiMap[K, V]) Each(do func(key K, value V)) {
+	for key, set := range m.m {
+		for value := range set {
+			do(key, value)
+		}
+	}
+}
+
+// ToMap returns a copy of the multimap as a map of value slices.
+func (m *SetMultiMap[K, V]) ToMap() map[K][]V {
+	return MapTransformBy(m.m, MapKeys[V, struct{}])
+}
+
+// Inverse returns a SetMultiMap view grouping the keys by value.
+func (m *SetMultiMap[K, V]) Inverse() MultiMap[V, K] {
+	return &SetMultiMap[V, K]{m: m.inverse, inverse: m.m, size: m.size}
+}
+
+func addSetEntry[K, V comparable](m map[K]map[V]struct{}, key K, value V) bool {
+	var set, ok = m[key]
+	if !ok {
+		set = make(map[V]struct{})
+		m[key] = set
+	}
+
+	if _, ok := set[value]; ok {
+		return false
+	}
+
+	set[value] = struct{}{}
+	return true
+}
+
+func removeSetEntry[K, V comparable](m map[K]map[V]struct{}, key K, value V) bool {
+	var set = m[key]
+	if _, ok := set[value]; !ok {
+		return false
+	}
+
+	delete(set, value)
+	if len(set) == 0 {
+		delete(m, key)
+	}
+
+	return true
+}
@@ -0,0 +1,154 @@
+package collection_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+var (
+	_ collection.MultiMap[string, int] = (*collection.ListMultiMap[string, int])(nil)
+	_ collection.MultiMap[string, int] = (*collection.SetMultiMap[string, int])(nil)
+)
+
+func sortedMultiMap[K, V comparable](m collection.MultiMap[K, V], less func(l, r V) int) map[K][]V {
+	var result = m.ToMap()
+	for _, values := range result {
+		slices.SortFunc(values, less)
+	}
+
+	return result
+}
+
+func compareInts(l, r int) int { return l - r }
+
+func TestListMultiMap(t *testing.T) {
+	m := collection.NewListMultiMap[string, int]()
+	m.Put("a", 1)
+	m.PutAll("a", 2, 1)
+	m.PutAll("b", 3)
+
+	if got := m.Get("a"); !slices.Equal(got, []int{1, 2, 1}) {
+		t.Errorf("Get(a) = %v; want [1 2 1]", got)
+	}
+
+	if m.KeyCount() != 2 || m.ValueCount() != 4 {
+		t.Errorf("KeyCount(), ValueCount() = %v, %v; want 2, 4", m.KeyCount(), m.ValueCount())
+	}
+
+	if !m.Remove("a", 1) || !slices.Equal(m.Get("a"), []int{2, 1}) {
+		t.Errorf("Remove(a, 1) left %v; want [2 1]", m.Get("a"))
+	}
+
+	if m.Remove("a", 5) {
+		t.Errorf("Remove(a, 5) = true; want false")
+	}
+
+	if !m.ContainsEntry("a", 2) || m.ContainsEntry("b", 2) {
+		t.Errorf("ContainsEntry() mismatch")
+	}
+
+	if got := m.RemoveAll("b"); !slices.Equal(got, []int{3}) || m.ContainsKey("b") {
+		t.Errorf("RemoveAll(b) = %v; want [3]", got)
+	}
+
+	m.Remove("a", 2)
+	m.Remove("a", 1)
+
+	if m.KeyCount() != 0 || m.ValueCount() != 0 || m.ContainsKey("a") {
+		t.Errorf("expected empty multimap, got %v", m.ToMap())
+	}
+}
+
+func TestSetMultiMap(t *testing.T) {
+	m := collection.NewSetMultiMap[string, int]()
+
+	if !m.Put("a", 1) || m.Put("a", 1) {
+		t.Errorf("Put() should report only the first insertion")
+	}
+
+	if !m.PutAll("a", 1, 2) || m.PutAll("a", 2) {
+		t.Errorf("PutAll() should report whether anything was added")
+	}
+
+	m.Put("b", 1)
+
+	if m.KeyCount() != 2 || m.ValueCount() != 3 {
+		t.Errorf("KeyCount(), ValueCount() = %v, %v; want 2, 3", m.KeyCount(), m.ValueCount())
+	}
+
+	want := map[string][]int{"a": {1, 2}, "b": {1}}
+	if got := sortedMultiMap[string, int](m, compareInts); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v; want %v", got, want)
+	}
+
+	if !m.Remove("b", 1) || m.ContainsKey("b") || m.ValueCount() != 2 {
+		t.Errorf("Remove(b, 1) did not drop empty group")
+	}
+
+	if got := m.RemoveAll("a"); len(got) != 2 || m.ValueCount() != 0 {
+		t.Errorf("RemoveAll(a) = %v; want 2 values", got)
+	}
+}
+
+func TestMultiMapInverse(t *testing.T) {
+	cases := []struct {
+		name string
+		m    collection.MultiMap[string, int]
+		want map[int][]string
+	}{
+		{name: "list", m: collection.NewListMultiMap[string, int](), want: map[int][]string{1: {"x", "y", "y", "z"}, 2: {"x"}}},
+		{name: "set", m: collection.NewSetMultiMap[string, int](), want: map[int][]string{1: {"x", "y", "z"}, 2: {"x"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.m.PutAll("x", 1, 2)
+			tc.m.PutAll("y", 1, 1)
+
+			inverse := tc.m.Inverse()
+			tc.m.PutAll("z", 1, 3)
+
+			if !inverse.ContainsEntry(3, "z") {
+				t.Errorf("Inverse() view misses later changes of the multimap")
+			}
+
+			if inverse.RemoveAll(3); tc.m.ContainsEntry("z", 3) || tc.m.ValueCount() != inverse.ValueCount() {
+				t.Errorf("changes of the Inverse() view are missing in the multimap: %v", tc.m.ToMap())
+			}
+
+			got := sortedMultiMap(inverse, func(l, r string) int {
+				if l < r {
+					return -1
+				}
+
+				if l > r {
+					return 1
+				}
+
+				return 0
+			})
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Inverse() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMultiMapFromGroups(t *testing.T) {
+	groups := collection.GroupBy([]int{1, 2, 3, 4, 5, 3}, func(v int) bool { return v%2 == 0 })
+
+	list := collection.ListMultiMapFromGroups(groups)
+	if !slices.Equal(list.Get(false), []int{1, 3, 5, 3}) || list.ValueCount() != 6 {
+		t.Errorf("ListMultiMapFromGroups() = %v", list.ToMap())
+	}
+
+	set := collection.SetMultiMapFromGroups(groups)
+	want := map[bool][]int{false: {1, 3, 5}, true: {2, 4}}
+	if got := sortedMultiMap[bool, int](set, compareInts); !reflect.DeepEqual(got, want) {
+		t.Errorf("SetMultiMapFromGroups() = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,390 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPath is returned for malformed paths.
+	ErrInvalidPath = errors.New("collection: invalid path")
+	// ErrPathNotFound is returned when a path addresses a missing key or index.
+	ErrPathNotFound = errors.New("collection: path not found")
+	// ErrPathType is returned when a path traverses a scalar or addresses a value of an unexpected type.
+	ErrPathType = errors.New("collection: unexpected value type at path")
+)
+
+// Paths address values of documents decoded from JSON, nested map[string]any and []any values.
+// A path is either a JSON Pointer (RFC 6901) such as "/users/0/name", or a dotted path such as
+// "users.0.name". Array elements are addressed by their index; the empty path addresses the document.
+
+// MapGetPath returns the value at the path of the document.
+func MapGetPath(doc map[string]any, path string) (any, error) {
+	var segments, err = parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var node any = doc
+	for i, segment := range segments {
+		if node, err = pathChild(node, segment); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, formatPath(segments[:i+1]))
+		}
+	}
+
+	return node, nil
+}
+
+// MapGetPathAs returns the value at the path of the document as T.
+// It returns an error wrapping ErrPathType if the value is not a T.
+// Note that numbers decoded by encoding/json are float64.
+func MapGetPathAs[T any](doc map[string]any, path string) (T, error) {
+	var value, err = MapGetPath(doc, path)
+	if err != nil {
+		return *new(T), err
+	}
+
+	result, ok := value.(T)
+	if !ok {
+		return result, fmt.Errorf("%w: %s is %T, not %T", ErrPathType, path, value, result)
+	}
+
+	return result, nil
+}
+
+// MapSetPath sets the value at the path of the document, creating missing intermediate maps.
+// An array index equal to the length of the array, or "-", appends to it.
+func MapSetPath(doc map[string]any, path string, value any) error {
+	return pathSetter{create: true}.set(doc, path, value)
+}
+
+// MapDeletePath removes the value at the path of the document. Removing an array element shifts the following ones.
+func MapDeletePath(doc map[string]any, path string) error {
+	var segments, err = parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		return fmt.Errorf("%w: can't delete the document", ErrInvalidPath)
+	}
+
+	_, err = deletePath(doc, segments, 0)
+
+	return err
+}
+
+// MapWalk calls fn for each leaf of the document with its dotted path, in lexicographic order of map keys.
+// Scalars, nil values, empty maps and empty arrays are leaves. If fn returns false, the walk stops.
+func MapWalk(doc map[string]any, fn func(path string, value any) bool) {
+	walkPath(nil, doc, fn)
+}
+
+// MapFlatten returns the leaves of the document by their dotted path.
+// Keys containing dots can't be told apart from nested keys.
+func MapFlatten(doc map[string]any) map[string]any {
+	var result = make(map[string]any)
+	MapWalk(doc, func(path string, value any) bool {
+		result[path] = value
+		return true
+	})
+
+	return result
+}
+
+// MapUnflatten builds a document from values by their dotted path, the inverse of MapFlatten.
+// Maps below the document whose keys are exactly the indices 0 to n-1 become arrays.
+// It returns an error wrapping ErrPathType if a path traverses a value set by another path.
+// The document shares no maps or arrays with source.
+func MapUnflatten(source map[string]any) (map[string]any, error) {
+	var (
+		result = make(map[string]any)
+		paths  = MapKeys(source)
+	)
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		var node = result
+		var segments = strings.Split(path, ".")
+
+		for i, segment := range segments[:len(segments)-1] {
+			var child, ok = node[segment].(map[string]any)
+			if !ok {
+				if _, exists := node[segment]; exists {
+					return nil, fmt.Errorf("%w: %s", ErrPathType, strings.Join(segments[:i+1], "."))
+				}
+
+				child = make(map[string]any)
+				node[segment] = child
+			}
+
+			node = child
+		}
+
+		var last = segments[len(segments)-1]
+		if _, exists := node[last]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrPathType, path)
+		}
+
+		node[last] = copyDocument(source[path])
+	}
+
+	for key, child := range result {
+		result[key] = restoreArrays(child)
+	}
+
+	return result, nil
+}
+
+func parsePath(path string) ([]string, error) {
+	switch {
+	case path == "":
+		return nil, nil
+	case strings.HasPrefix(path, "/"):
+		var segments = strings.Split(path[1:], "/")
+		for i, segment := range segments {
+			var unescaped = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+			if strings.Count(segment, "~") != strings.Count(segment, "~0")+strings.Count(segment, "~1") {
+				return nil, fmt.Errorf("%w: bad escape in %q", ErrInvalidPath, path)
+			}
+
+			segments[i] = unescaped
+		}
+
+		return segments, nil
+	}
+
+	return strings.Split(path, "."), nil
+}
+
+// formatPath formats the segments as a JSON Pointer.
+func formatPath(segments []string) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		builder.WriteByte('/')
+		builder.WriteString(strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1"))
+	}
+
+	return builder.String()
+}
+
+func pathChild(node any, segment string) (any, error) {
+	switch n := node.(type) {
+	case map[string]any:
+		if value, ok := n[segment]; ok {
+			return value, nil
+		}
+	case []any:
+		if i, ok := arrayIndex(segment, len(n)); ok && i < len(n) {
+			return n[i], nil
+		}
+	default:
+		return nil, ErrPathType
+	}
+
+	return nil, ErrPathNotFound
+}
+
+// arrayIndex parses an array index, "-" denoting the position after the last element.
+func arrayIndex(segment string, length int) (int, bool) {
+	if segment == "-" {
+		return length, true
+	}
+
+	if segment == "" || len(segment) > 1 && segment[0] == '0' {
+		return 0, false
+	}
+
+	var i, err = strconv.Atoi(segment)
+
+	return i, err == nil && i >= 0
+}
+
+// pathSetter sets values at paths. With insert, array elements are inserted rather than replaced,
+// and with create, missing intermediate maps are created rather than reported.
+type pathSetter struct {
+	insert bool
+	create bool
+}
+
+func (p pathSetter) set(doc map[string]any, path string, value any) error {
+	var segments, err = parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		return fmt.Errorf("%w: can't replace the document", ErrInvalidPath)
+	}
+
+	_, err = p.setPath(doc, segments, 0, value)
+
+	return err
+}
+
+// setPath sets the value at segments[depth:] below node and returns the updated node, which differs
+// from node if an array grew or an intermediate map was created.
+func (p pathSetter) setPath(node any, segments []string, depth int, value any) (any, error) {
+	if depth == len(segments) {
+		return value, nil
+	}
+
+	var segment = segments[depth]
+
+	switch n := node.(type) {
+	case nil:
+		if p.create {
+			return p.setPath(make(map[string]any), segments, depth, value)
+		}
+	case map[string]any:
+		var child, ok = n[segment]
+		if !ok && !p.create && depth < len(segments)-1 {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPath(segments[:depth+1]))
+		}
+
+		child, err := p.setPath(child, segments, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+
+		n[segment] = child
+
+		return n, nil
+	case []any:
+		var i, ok = arrayIndex(segment, len(n))
+		if !ok || i > len(n) {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPath(segments[:depth+1]))
+		}
+
+		if i == len(n) || p.insert && depth == len(segments)-1 {
+			if depth < len(segments)-1 {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPath(segments[:depth+1]))
+			}
+
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+
+			return n, nil
+		}
+
+		var child, err = p.setPath(n[i], segments, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+
+		n[i] = child
+
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPathType, formatPath(segments[:depth]))
+}
+
+// deletePath removes the value at segments[depth:] below node and returns the updated node.
+func deletePath(node any, segments []string, depth int) (any, error) {
+	var segment = segments[depth]
+
+	switch n := node.(type) {
+	case map[string]any:
+		var child, ok = n[segment]
+		if !ok {
+			break
+		}
+
+		if depth == len(segments)-1 {
+			delete(n, segment)
+			return n, nil
+		}
+
+		child, err := deletePath(child, segments, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		n[segment] = child
+
+		return n, nil
+	case []any:
+		var i, ok = arrayIndex(segment, len(n))
+		if !ok || i >= len(n) {
+			break
+		}
+
+		if depth == len(segments)-1 {
+			return append(n[:i:i], n[i+1:]...), nil
+		}
+
+		child, err := deletePath(n[i], segments, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		n[i] = child
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrPathType, formatPath(segments[:depth]))
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPath(segments[:depth+1]))
+}
+
+func walkPath(segments []string, node any, fn func(path string, value any) bool) bool {
+	switch n := node.(type) {
+	case map[string]any:
+		if len(n) > 0 || segments == nil {
+			var keys = MapKeys(n)
+			sort.Strings(keys)
+
+			return All(keys, func(key string) bool {
+				return walkPath(append(segments[:len(segments):len(segments)], key), n[key], fn)
+			})
+		}
+	case []any:
+		if len(n) > 0 {
+			for i, child := range n {
+				if !walkPath(append(segments[:len(segments):len(segments)], strconv.Itoa(i)), child, fn) {
+					return false
+				}
+			}
+
+			return true
+		}
+	}
+
+	return fn(strings.Join(segments, "."), node)
+}
+
+// restoreArrays replaces maps whose keys are exactly the indices 0 to n-1 with arrays.
+func restoreArrays(node any) any {
+	var n, ok = node.(map[string]any)
+	if !ok {
+		return node
+	}
+
+	for key, child := range n {
+		n[key] = restoreArrays(child)
+	}
+
+	if len(n) == 0 {
+		return n
+	}
+
+	for i := 0; i < len(n); i++ {
+		if _, ok := n[strconv.Itoa(i)]; !ok {
+			return n
+		}
+	}
+
+	var result = make([]any, len(n))
+	for i := range result {
+		result[i] = n[strconv.Itoa(i)]
+	}
+
+	return result
+}
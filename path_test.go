@@ -0,0 +1,231 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func decodeDocument(t *testing.T, source string) map[string]any {
+	t.Helper()
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(source), &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	return doc
+}
+
+func TestMapGetPath(t *testing.T) {
+	var doc = decodeDocument(t, `{"users": [{"name": "ann", "tags": ["admin"]}], "a/b": {"m~n": 1}, "empty": null}`)
+
+	testCases := []struct {
+		name string
+		path string
+		want any
+		err  error
+	}{
+		{name: "dotted", path: "users.0.name", want: "ann"},
+		{name: "pointer", path: "/users/0/tags/0", want: "admin"},
+		{name: "escaped pointer", path: "/a~1b/m~0n", want: 1.0},
+		{name: "null", path: "empty", want: nil},
+		{name: "root", path: "", want: doc},
+		{name: "missing key", path: "users.0.email", err: collection.ErrPathNotFound},
+		{name: "index out of range", path: "users.1", err: collection.ErrPathNotFound},
+		{name: "leading zero", path: "users.00", err: collection.ErrPathNotFound},
+		{name: "scalar", path: "users.0.name.first", err: collection.ErrPathType},
+		{name: "bad escape", path: "/a~2b", err: collection.ErrInvalidPath},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := collection.MapGetPath(doc, tc.path)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("MapGetPath(%q) error = %v; want %v", tc.path, err, tc.err)
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MapGetPath(%q) = %v; want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapGetPathAs(t *testing.T) {
+	var doc = decodeDocument(t, `{"server": {"host": "localhost", "port": 8080}}`)
+
+	if host, err := collection.MapGetPathAs[string](doc, "server.host"); err != nil || host != "localhost" {
+		t.Errorf("MapGetPathAs[string]() = %q, %v; want localhost", host, err)
+	}
+
+	if port, err := collection.MapGetPathAs[float64](doc, "/server/port"); err != nil || port != 8080 {
+		t.Errorf("MapGetPathAs[float64]() = %v, %v; want 8080", port, err)
+	}
+
+	if _, err := collection.MapGetPathAs[string](doc, "server.port"); !errors.Is(err, collection.ErrPathType) {
+		t.Errorf("MapGetPathAs[string]() error = %v; want ErrPathType", err)
+	}
+
+	if _, err := collection.MapGetPathAs[string](doc, "server.user"); !errors.Is(err, collection.ErrPathNotFound) {
+		t.Errorf("MapGetPathAs[string]() error = %v; want ErrPathNotFound", err)
+	}
+}
+
+func TestMapSetPath(t *testing.T) {
+	var doc = decodeDocument(t, `{"users": [{"name": "ann"}], "count": 1}`)
+
+	for _, kv := range []collection.KV[string, any]{
+		{Key: "users.0.name", Value: "bob"},
+		{Key: "/users/-", Value: map[string]any{"name": "eve"}},
+		{Key: "users.2", Value: map[string]any{"name": "joe"}},
+		{Key: "settings.theme.dark", Value: true},
+	} {
+		if err := collection.MapSetPath(doc, kv.Key, kv.Value); err != nil {
+			t.Fatalf("MapSetPath(%q) error = %v", kv.Key, err)
+		}
+	}
+
+	want := decodeDocument(t, `{"users": [{"name": "bob"}, {"name": "eve"}, {"name": "joe"}], "count": 1, "settings": {"theme": {"dark": true}}}`)
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("MapSetPath() = %v; want %v", doc, want)
+	}
+
+	for path, err := range map[string]error{
+		"users.5":      collection.ErrPathNotFound,
+		"users.-.name": collection.ErrPathNotFound,
+		"count.value":  collection.ErrPathType,
+		"":             collection.ErrInvalidPath,
+	} {
+		if got := collection.MapSetPath(doc, path, 1); !errors.Is(got, err) {
+			t.Errorf("MapSetPath(%q) error = %v; want %v", path, got, err)
+		}
+	}
+}
+
+func TestMapDeletePath(t *testing.T) {
+	var doc = decodeDocument(t, `{"users": [{"name": "ann"}, {"name": "bob", "age": 30}], "count": 2}`)
+
+	for _, path := range []string{"users.0", "/users/0/age", "count"} {
+		if err := collection.MapDeletePath(doc, path); err != nil {
+			t.Fatalf("MapDeletePath(%q) error = %v", path, err)
+		}
+	}
+
+	want := decodeDocument(t, `{"users": [{"name": "bob"}]}`)
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("MapDeletePath() = %v; want %v", doc, want)
+	}
+
+	for path, err := range map[string]error{
+		"count":             collection.ErrPathNotFound,
+		"users.1":           collection.ErrPathNotFound,
+		"users.0.name.last": collection.ErrPathType,
+		"":                  collection.ErrInvalidPath,
+	} {
+		if got := collection.MapDeletePath(doc, path); !errors.Is(got, err) {
+			t.Errorf("MapDeletePath(%q) error = %v; want %v", path, got, err)
+		}
+	}
+}
+
+func TestMapWalk(t *testing.T) {
+	var (
+		doc   = decodeDocument(t, `{"b": [1, {"c": null}], "a": {}, "d": []}`)
+		paths []string
+	)
+
+	collection.MapWalk(doc, func(path string, value any) bool {
+		paths = append(paths, path)
+		return true
+	})
+
+	if want := []string{"a", "b.0", "b.1.c", "d"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("MapWalk() visited %v; want %v", paths, want)
+	}
+
+	paths = nil
+	collection.MapWalk(doc, func(path string, value any) bool {
+		paths = append(paths, path)
+		return len(paths) < 2
+	})
+
+	if want := []string{"a", "b.0"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("MapWalk() with early exit visited %v; want %v", paths, want)
+	}
+}
+
+func TestMapFlatten(t *testing.T) {
+	var doc = decodeDocument(t, `{"server": {"hosts": ["a", "b"], "port": 80}, "tags": {}, "debug": false}`)
+
+	flat := collection.MapFlatten(doc)
+
+	want := map[string]any{
+		"server.hosts.0": "a",
+		"server.hosts.1": "b",
+		"server.port":    80.0,
+		"tags":           map[string]any{},
+		"debug":          false,
+	}
+
+	if !reflect.DeepEqual(flat, want) {
+		t.Errorf("MapFlatten() = %v; want %v", flat, want)
+	}
+
+	got, err := collection.MapUnflatten(flat)
+	if err != nil || !reflect.DeepEqual(got, doc) {
+		t.Errorf("MapUnflatten() = %v, %v; want %v", got, err, doc)
+	}
+
+	indexed := map[string]any{"0": "a", "1": map[string]any{"0": "b"}}
+	if got, err := collection.MapUnflatten(collection.MapFlatten(indexed)); err != nil || !reflect.DeepEqual(got, map[string]any{"0": "a", "1": []any{"b"}}) {
+		t.Errorf("MapUnflatten() = %v, %v; want the root kept as a map", got, err)
+	}
+
+	source := map[string]any{"tags": map[string]any{}, "tags.x": 1}
+	if got, err := collection.MapUnflatten(source); err != nil || !reflect.DeepEqual(got, map[string]any{"tags": map[string]any{"x": 1}}) {
+		t.Errorf("MapUnflatten() = %v, %v; want map[tags:map[x:1]]", got, err)
+	}
+
+	if !reflect.DeepEqual(source["tags"], map[string]any{}) {
+		t.Errorf("MapUnflatten() modified the source: %v", source)
+	}
+
+	if _, err := collection.MapUnflatten(map[string]any{"a": 1, "a.b": 2}); !errors.Is(err, collection.ErrPathType) {
+		t.Errorf("MapUnflatten() error = %v; want ErrPathType", err)
+	}
+}
+
+// ExampleMapGetPath: Example function demonstrating the use of the MapGetPath function.
+func ExampleMapGetPath() {
+	var doc map[string]any
+	_ = json.Unmarshal([]byte(`{"users": [{"name": "ann", "roles": ["admin"]}]}`), &doc)
+
+	name, _ := collection.MapGetPath(doc, "users.0.name")
+	role, _ := collection.MapGetPath(doc, "/users/0/roles/0")
+	_, err := collection.MapGetPath(doc, "users.1")
+
+	fmt.Println(name, role)
+	fmt.Println(err)
+	// Output:
+	// ann admin
+	// collection: path not found: /users/1
+}
+
+// ExampleMapFlatten: Example function demonstrating the use of the MapFlatten function.
+func ExampleMapFlatten() {
+	var doc = map[string]any{"server": map[string]any{"port": 80, "hosts": []any{"a", "b"}}}
+
+	collection.MapWalk(collection.MapFlatten(doc), func(path string, value any) bool {
+		fmt.Println(path, value)
+		return true
+	})
+	// Output:
+	// server.hosts.0 a
+	// server.hosts.1 b
+	// server.port 80
+}
@@ -0,0 +1,556 @@
+package collection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Codec encodes and decodes values of type T to and from bytes.
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec backed by encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode encodes value as JSON.
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode decodes a JSON encoded value.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// GobCodec is a Codec backed by encoding/gob.
+type GobCodec[T any] struct{}
+
+// Encode encodes value with encoding/gob.
+func (GobCodec[T]) Encode(value T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode decodes a gob encoded value.
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// FsyncPolicy controls when the write-ahead log is flushed to stable storage.
+type FsyncPolicy int
+
+const (
+	// FsyncAlways syncs the log after every mutation.
+	FsyncAlways FsyncPolicy = iota
+	// FsyncInterval syncs the log on the first mutation after FsyncInterval has elapsed since the last sync.
+	FsyncInterval
+	// FsyncNever leaves flushing to the operating system; the log is still synced on Compact and Close.
+	FsyncNever
+)
+
+const (
+	persistentSnapshotFile = "snapshot"
+	persistentLogFile      = "wal"
+
+	defaultCompactThreshold = 1024
+
+	// walHeaderSize is the size of the record header: the payload length, its checksum and the payload checksum.
+	walHeaderSize = 12
+)
+
+// PersistentMapOptions configures OpenPersistentMap. The zero value uses JSON codecs,
+// FsyncAlways and compaction after 1024 log records.
+type PersistentMapOptions[K comparable, V any] struct {
+	// KeyCodec encodes keys, defaults to JSONCodec.
+	KeyCodec Codec[K]
+	// ValueCodec encodes values, defaults to JSONCodec.
+	ValueCodec Codec[V]
+	// Fsync is the log sync policy.
+	Fsync FsyncPolicy
+	// FsyncInterval is the minimal time between syncs for FsyncInterval policy.
+	FsyncInterval time.Duration
+	// CompactThreshold is the number of log records that triggers compaction.
+	// Negative value disables automatic compaction.
+	CompactThreshold int
+}
+
+var (
+	// ErrPersistentMapClosed is returned by mutations on a closed PersistentMap.
+	ErrPersistentMapClosed = errors.New("collection: persistent map is closed")
+	// ErrPersistentMapCorrupted is returned by OpenPersistentMap when a snapshot or log record
+	// fails its checksums, or a snapshot record is cut short.
+	ErrPersistentMapCorrupted = errors.New("collection: persistent map is corrupted")
+	// ErrNotDurable is returned by mutations that were logged and applied, but whose log sync failed.
+	ErrNotDurable = errors.New("collection: mutation applied but not durable")
+)
+
+type walOp byte
+
+const (
+	walSet walOp = iota + 1
+	walDelete
+	walClear
+)
+
+// PersistentMap is a SafeMap that survives restarts. Every mutation is appended to
+// a write-ahead log before it is applied, and the log is periodically compacted into a snapshot.
+type PersistentMap[K comparable, V any] struct {
+	m *SafeMap[K, V]
+
+	mu       sync.Mutex
+	dir      string
+	opts     PersistentMapOptions[K, V]
+	log      *os.File
+	offset   int64
+	records  int
+	lastSync time.Time
+	closed   bool
+	// compactErr is the error of the last failed automatic compaction, reported by Compact.
+	compactErr error
+}
+
+// OpenPersistentMap opens or creates a persistent map stored in dir.
+// The snapshot and the log are replayed; a final log record cut short by the end of the file
+// is treated as an interrupted write and discarded. A record failing its checksums fails
+// with an error wrapping ErrPersistentMapCorrupted and leaves the files untouched.
+func OpenPersistentMap[K comparable, V any](dir string, opts PersistentMapOptions[K, V]) (*PersistentMap[K, V], error) {
+	if opts.KeyCodec == nil {
+		opts.KeyCodec = JSONCodec[K]{}
+	}
+
+	if opts.ValueCodec == nil {
+		opts.ValueCodec = JSONCodec[V]{}
+	}
+
+	if opts.CompactThreshold == 0 {
+		opts.CompactThreshold = defaultCompactThreshold
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	p := &PersistentMap[K, V]{
+		m:        NewSafeMap[K, V](),
+		dir:      dir,
+		opts:     opts,
+		lastSync: time.Now(),
+	}
+
+	if _, _, err := p.replay(filepath.Join(dir, persistentSnapshotFile), false); err != nil {
+		return nil, fmt.Errorf("collection: replay snapshot: %w", err)
+	}
+
+	logPath := filepath.Join(dir, persistentLogFile)
+
+	valid, records, err := p.replay(logPath, true)
+	if err != nil {
+		return nil, fmt.Errorf("collection: replay log: %w", err)
+	}
+
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := log.Truncate(valid); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	if _, err := log.Seek(valid, io.SeekStart); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	p.log = log
+	p.offset = valid
+	p.records = records
+
+	return p, nil
+}
+
+// Get returns the value stored for a key.
+func (p *PersistentMap[K, V]) Get(key K) (V, bool) {
+	return p.m.Get(key)
+}
+
+// Has reports whether the key is present.
+func (p *PersistentMap[K, V]) Has(key K) bool {
+	return p.m.Has(key)
+}
+
+// Len returns the number of entries.
+func (p *PersistentMap[K, V]) Len() int {
+	return p.m.Len()
+}
+
+// Keys returns all keys in unspecified order.
+func (p *PersistentMap[K, V]) Keys() []K {
+	return p.m.Keys()
+}
+
+// Snapshot returns a point-in-time copy of the map contents.
+func (p *PersistentMap[K, V]) Snapshot() map[K]V {
+	return p.m.Snapshot()
+}
+
+// Range calls fn for each key-value pair of a snapshot of the map.
+// If fn returns false, range stops the iteration.
+func (p *PersistentMap[K, V]) Range(fn func(key K, value V) bool) {
+	p.m.Range(fn)
+}
+
+// Set stores the value for a key. If the log sync fails, the value is stored nevertheless
+// and Set returns an error wrapping ErrNotDurable.
+func (p *PersistentMap[K, V]) Set(key K, value V) error {
+	rawKey, err := p.opts.KeyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	rawValue, err := p.opts.ValueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	return p.mutate(walSet, rawKey, rawValue, func() { p.m.Set(key, value) })
+}
+
+// Delete deletes the value for a key. Like Set, it returns an error wrapping ErrNotDurable
+// if only the log sync failed.
+func (p *PersistentMap[K, V]) Delete(key K) error {
+	rawKey, err := p.opts.KeyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	return p.mutate(walDelete, rawKey, nil, func() { p.m.Delete(key) })
+}
+
+// Clear removes all entries. Like Set, it returns an error wrapping ErrNotDurable
+// if only the log sync failed.
+func (p *PersistentMap[K, V]) Clear() error {
+	return p.mutate(walClear, nil, nil, p.m.Clear)
+}
+
+// Compact writes the current state into a new snapshot and truncates the log.
+// The error of the last failed automatic compaction, if any, is joined with its own.
+func (p *PersistentMap[K, V]) Compact() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPersistentMapClosed
+	}
+
+	var err = p.compactErr
+	p.compactErr = nil
+
+	return errors.Join(err, p.compact())
+}
+
+// Sync flushes the log to stable storage.
+func (p *PersistentMap[K, V]) Sync() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPersistentMapClosed
+	}
+
+	return p.sync()
+}
+
+// Close syncs and closes the log. The in-memory contents stay readable.
+func (p *PersistentMap[K, V]) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+
+	return errors.Join(p.log.Sync(), p.log.Close())
+}
+
+func (p *PersistentMap[K, V]) mutate(op walOp, key []byte, value []byte, apply func()) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPersistentMapClosed
+	}
+
+	var record = encodeWALRecord(op, key, value)
+	if _, err := p.log.Write(record); err != nil {
+		// Drop the torn record, otherwise every later record would be lost on replay.
+		var dropErr = p.log.Truncate(p.offset)
+		if dropErr == nil {
+			_, dropErr = p.log.Seek(p.offset, io.SeekStart)
+		}
+
+		return errors.Join(err, dropErr)
+	}
+
+	p.offset += int64(len(record))
+	p.records++
+
+	// The record is replayed after a restart even if the sync fails, so the mutation is applied regardless.
+	var err error
+	if p.opts.Fsync == FsyncAlways || p.opts.Fsync == FsyncInterval && time.Since(p.lastSync) >= p.opts.FsyncInterval {
+		if syncErr := p.sync(); syncErr != nil {
+			err = fmt.Errorf("%w: %w", ErrNotDurable, syncErr)
+		}
+	}
+
+	apply()
+
+	// The mutation is logged, so a failed compaction only delays shrinking the log and is reported by Compact.
+	if p.opts.CompactThreshold > 0 && p.records >= p.opts.CompactThreshold {
+		p.compactErr = p.compact()
+	}
+
+	return err
+}
+
+func (p *PersistentMap[K, V]) sync() error {
+	p.lastSync = time.Now()
+	return p.log.Sync()
+}
+
+func (p *PersistentMap[K, V]) compact() error {
+	var (
+		snapshotPath = filepath.Join(p.dir, persistentSnapshotFile)
+		tmpPath      = snapshotPath + ".tmp"
+	)
+
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if err := p.writeSnapshot(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, snapshotPath); err != nil {
+		return err
+	}
+
+	// Replaying the old log over the new snapshot is idempotent,
+	// so a crash before the truncation below loses nothing.
+	if err := syncDir(p.dir); err != nil {
+		return err
+	}
+
+	if err := p.log.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := p.log.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	p.offset = 0
+	p.records = 0
+
+	return p.sync()
+}
+
+func (p *PersistentMap[K, V]) writeSnapshot(f *os.File) error {
+	var w = bufio.NewWriter(f)
+
+	for key, value := range p.m.Snapshot() {
+		rawKey, err := p.opts.KeyCodec.Encode(key)
+		if err != nil {
+			return err
+		}
+
+		rawValue, err := p.opts.ValueCodec.Encode(value)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(encodeWALRecord(walSet, rawKey, rawValue)); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// replay applies the records stored in path and returns the length of its valid prefix
+// and the number of records in it. A missing file is empty. When tolerant is set,
+// a final record cut short by the end of the file ends the replay instead of failing it.
+func (p *PersistentMap[K, V]) replay(path string, tolerant bool) (valid int64, records int, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer f.Close()
+
+	var r = bufio.NewReader(f)
+
+	for {
+		op, key, value, n, err := decodeWALRecord(r)
+		if err == io.EOF || tolerant && err == io.ErrUnexpectedEOF {
+			return valid, records, nil
+		}
+
+		if err == io.ErrUnexpectedEOF {
+			err = ErrPersistentMapCorrupted
+		}
+
+		if err != nil {
+			return valid, records, fmt.Errorf("%w: record at offset %d", err, valid)
+		}
+
+		if err := p.apply(op, key, value); err != nil {
+			return valid, records, err
+		}
+
+		valid += n
+		records++
+	}
+}
+
+func (p *PersistentMap[K, V]) apply(op walOp, rawKey []byte, rawValue []byte) error {
+	if op == walClear {
+		p.m.Clear()
+		return nil
+	}
+
+	key, err := p.opts.KeyCodec.Decode(rawKey)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case walSet:
+		value, err := p.opts.ValueCodec.Decode(rawValue)
+		if err != nil {
+			return err
+		}
+
+		p.m.Set(key, value)
+	case walDelete:
+		p.m.Delete(key)
+	default:
+		return fmt.Errorf("collection: unknown log operation %d", op)
+	}
+
+	return nil
+}
+
+// encodeWALRecord lays out a record as
+// [payload length uint32][crc32 uint32][op byte][key length uvarint][key][value].
+func encodeWALRecord(op walOp, key []byte, value []byte) []byte {
+	var payload = make([]byte, 0, 1+binary.MaxVarintLen64+len(key)+len(value))
+	payload = append(payload, byte(op))
+	payload = binary.AppendUvarint(payload, uint64(len(key)))
+	payload = append(payload, key...)
+	payload = append(payload, value...)
+
+	var record = make([]byte, walHeaderSize, walHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(record[0:4]))
+	binary.LittleEndian.PutUint32(record[8:12], crc32.ChecksumIEEE(payload))
+
+	return append(record, payload...)
+}
+
+// decodeWALRecord reads a record and returns its length. It returns io.EOF at the end of the data,
+// io.ErrUnexpectedEOF for a record cut short by the end of the data, and ErrPersistentMapCorrupted
+// for a record failing its checksums.
+func decodeWALRecord(r io.Reader) (op walOp, key []byte, value []byte, n int64, err error) {
+	var header [walHeaderSize]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return
+	}
+
+	// The length has its own checksum, so a corrupted length isn't mistaken for a record cut short.
+	if crc32.ChecksumIEEE(header[0:4]) != binary.LittleEndian.Uint32(header[4:8]) {
+		err = ErrPersistentMapCorrupted
+		return
+	}
+
+	var size = int64(binary.LittleEndian.Uint32(header[0:4]))
+	n = int64(len(header)) + size
+
+	// ReadAll grows the buffer as data arrives, so a large length can't force a huge allocation.
+	payload, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return
+	}
+
+	if int64(len(payload)) != size {
+		err = io.ErrUnexpectedEOF
+		return
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[8:12]) || len(payload) == 0 {
+		err = ErrPersistentMapCorrupted
+		return
+	}
+
+	keyLen, keySize := binary.Uvarint(payload[1:])
+	if keySize <= 0 || uint64(len(payload)-1-keySize) < keyLen {
+		err = ErrPersistentMapCorrupted
+		return
+	}
+
+	var body = payload[1+keySize:]
+
+	return walOp(payload[0]), body[:keyLen], body[keyLen:], n, nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer d.Close()
+
+	return d.Sync()
+}
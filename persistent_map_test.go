@@ -0,0 +1,259 @@
+package collection_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type account struct {
+	Name    string
+	Balance int
+}
+
+func openPersistentMap[K comparable, V any](t *testing.T, dir string, opts collection.PersistentMapOptions[K, V]) *collection.PersistentMap[K, V] {
+	t.Helper()
+
+	m, err := collection.OpenPersistentMap(dir, opts)
+	if err != nil {
+		t.Fatalf("OpenPersistentMap() error = %v", err)
+	}
+
+	return m
+}
+
+func TestPersistentMapReplay(t *testing.T) {
+	cases := []struct {
+		name string
+		opts collection.PersistentMapOptions[string, account]
+	}{
+		{name: "json", opts: collection.PersistentMapOptions[string, account]{}},
+		{name: "gob", opts: collection.PersistentMapOptions[string, account]{
+			KeyCodec:   collection.GobCodec[string]{},
+			ValueCodec: collection.GobCodec[account]{},
+			Fsync:      collection.FsyncNever,
+		}},
+		{name: "compaction", opts: collection.PersistentMapOptions[string, account]{
+			Fsync:            collection.FsyncInterval,
+			CompactThreshold: 2,
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			m := openPersistentMap(t, dir, tc.opts)
+			for _, err := range []error{
+				m.Set("alice", account{Name: "Alice", Balance: 10}),
+				m.Set("bob", account{Name: "Bob", Balance: 20}),
+				m.Clear(),
+				m.Set("carol", account{Name: "Carol", Balance: 30}),
+				m.Set("dave", account{Name: "Dave", Balance: 40}),
+				m.Delete("carol"),
+				m.Set("dave", account{Name: "Dave", Balance: 45}),
+			} {
+				if err != nil {
+					t.Fatalf("mutation error = %v", err)
+				}
+			}
+
+			if err := m.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			reopened := openPersistentMap(t, dir, tc.opts)
+			defer reopened.Close()
+
+			want := map[string]account{"dave": {Name: "Dave", Balance: 45}}
+			if got := reopened.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Errorf("replayed = %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPersistentMapCompact(t *testing.T) {
+	dir := t.TempDir()
+	opts := collection.PersistentMapOptions[int, string]{CompactThreshold: -1}
+
+	m := openPersistentMap(t, dir, opts)
+	for i := 0; i < 10; i++ {
+		if err := m.Set(i%3, "value"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	if err := m.Compact(); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, "wal")); err != nil || info.Size() != 0 {
+		t.Errorf("log after Compact() = (%v, %v); want empty", info, err)
+	}
+
+	if err := m.Set(5, "after"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	m.Close()
+
+	if err := m.Set(6, "closed"); !errors.Is(err, collection.ErrPersistentMapClosed) {
+		t.Errorf("Set() after Close() error = %v; want %v", err, collection.ErrPersistentMapClosed)
+	}
+
+	reopened := openPersistentMap(t, dir, opts)
+	defer reopened.Close()
+
+	want := map[int]string{0: "value", 1: "value", 2: "value", 5: "after"}
+	if got := reopened.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed = %v; want %v", got, want)
+	}
+}
+
+func TestPersistentMapCrashRecovery(t *testing.T) {
+	cases := []struct {
+		name    string
+		corrupt func(t *testing.T, path string, lastRecord int64)
+	}{
+		{name: "truncated payload", corrupt: func(t *testing.T, path string, lastRecord int64) {
+			info, _ := os.Stat(path)
+			if err := os.Truncate(path, info.Size()-3); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{name: "truncated header", corrupt: func(t *testing.T, path string, lastRecord int64) {
+			if err := os.Truncate(path, lastRecord+5); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wal")
+
+			m := openPersistentMap(t, dir, collection.PersistentMapOptions[string, int]{})
+			m.Set("a", 1)
+			m.Set("b", 2)
+
+			info, _ := os.Stat(path)
+			lastRecord := info.Size()
+
+			m.Set("c", 3)
+			m.Close()
+
+			tc.corrupt(t, path, lastRecord)
+
+			recovered := openPersistentMap(t, dir, collection.PersistentMapOptions[string, int]{})
+
+			want := map[string]int{"a": 1, "b": 2}
+			if got := recovered.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Errorf("recovered = %v; want %v", got, want)
+			}
+
+			if info, _ := os.Stat(path); info.Size() != lastRecord {
+				t.Errorf("log size = %v; want torn tail truncated to %v", info.Size(), lastRecord)
+			}
+
+			recovered.Set("d", 4)
+			recovered.Close()
+
+			reopened := openPersistentMap(t, dir, collection.PersistentMapOptions[string, int]{})
+			defer reopened.Close()
+
+			want["d"] = 4
+			if got := reopened.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Errorf("reopened = %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPersistentMapCorruptedRecord(t *testing.T) {
+	cases := []struct {
+		name    string
+		corrupt func(data []byte, secondRecord int64)
+	}{
+		{name: "flipped payload", corrupt: func(data []byte, secondRecord int64) {
+			data[secondRecord+14] ^= 0xff
+		}},
+		{name: "flipped length", corrupt: func(data []byte, secondRecord int64) {
+			data[secondRecord] ^= 0x40
+		}},
+		{name: "flipped final byte", corrupt: func(data []byte, secondRecord int64) {
+			data[len(data)-1] ^= 0xff
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wal")
+
+			m := openPersistentMap(t, dir, collection.PersistentMapOptions[string, int]{})
+			m.Set("a", 1)
+
+			info, _ := os.Stat(path)
+			secondRecord := info.Size()
+
+			m.Set("b", 2)
+			m.Set("c", 3)
+			m.Set("d", 4)
+			m.Close()
+
+			data, _ := os.ReadFile(path)
+			tc.corrupt(data, secondRecord)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := collection.OpenPersistentMap(dir, collection.PersistentMapOptions[string, int]{}); !errors.Is(err, collection.ErrPersistentMapCorrupted) {
+				t.Errorf("OpenPersistentMap() error = %v; want ErrPersistentMapCorrupted", err)
+			}
+
+			if got, _ := os.ReadFile(path); !reflect.DeepEqual(got, data) {
+				t.Errorf("OpenPersistentMap() modified a corrupted log")
+			}
+		})
+	}
+}
+
+func TestPersistentMapCompactionFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	// A directory in place of the temporary snapshot makes compaction fail.
+	if err := os.Mkdir(filepath.Join(dir, "snapshot.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := openPersistentMap(t, dir, collection.PersistentMapOptions[string, int]{CompactThreshold: 2})
+	defer m.Close()
+
+	for i, key := range []string{"a", "b", "c"} {
+		if err := m.Set(key, i); err != nil {
+			t.Fatalf("Set(%q) error = %v; want compaction failure deferred", key, err)
+		}
+	}
+
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d; want 3", m.Len())
+	}
+
+	if err := m.Compact(); err == nil {
+		t.Errorf("Compact() error = nil; want the compaction failure")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "snapshot.tmp")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Compact(); err != nil {
+		t.Errorf("Compact() error = %v", err)
+	}
+}
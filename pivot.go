@@ -0,0 +1,146 @@
+package collection
+
+import (
+	"slices"
+	"sort"
+)
+
+// PivotTable is a table of aggregated values with ordered row and column headers,
+// as built by Pivot. Cells[i][j] holds the value of Rows[i] and Columns[j].
+type PivotTable[R, C comparable, V any] struct {
+	Rows    []R
+	Columns []C
+	Cells   [][]V
+	present [][]bool
+}
+
+// Pivot reshapes the elements into a table with a row per row key and a column per column key,
+// aggregating the elements sharing both keys into a cell with GroupByAggregateOrdered.
+// Rows and columns are ordered by first occurrence; cells without elements hold the missing value.
+func Pivot[S ~[]T, T any, R, C comparable, V any](source S, rowKey func(T) R, colKey func(T) C, aggregator Aggregator[T, V], missing V) *PivotTable[R, C, V] {
+	var (
+		result  = &PivotTable[R, C, V]{}
+		rows    = make(map[R]int)
+		columns = make(map[C]int)
+		cells   = GroupByAggregateOrdered(source, func(v T) Pair[R, C] {
+			return Pair[R, C]{First: rowKey(v), Second: colKey(v)}
+		}, aggregator)
+	)
+
+	// The groups are in order of first occurrence, so their keys are as well.
+	for _, cell := range cells {
+		if _, ok := rows[cell.Key.First]; !ok {
+			rows[cell.Key.First] = len(result.Rows)
+			result.Rows = append(result.Rows, cell.Key.First)
+		}
+
+		if _, ok := columns[cell.Key.Second]; !ok {
+			columns[cell.Key.Second] = len(result.Columns)
+			result.Columns = append(result.Columns, cell.Key.Second)
+		}
+	}
+
+	result.Cells = make([][]V, len(result.Rows))
+	result.present = make([][]bool, len(result.Rows))
+
+	for i := range result.Rows {
+		result.Cells[i] = make([]V, len(result.Columns))
+		result.present[i] = make([]bool, len(result.Columns))
+
+		for j := range result.Columns {
+			result.Cells[i][j] = missing
+		}
+	}
+
+	for _, cell := range cells {
+		var i, j = rows[cell.Key.First], columns[cell.Key.Second]
+		result.Cells[i][j] = cell.Value
+		result.present[i][j] = true
+	}
+
+	return result
+}
+
+// Get returns the value of the cell at the row and column.
+// The ok result is false if the table has no such row or column, or no elements were aggregated into the cell.
+func (t *PivotTable[R, C, V]) Get(row R, column C) (value V, ok bool) {
+	var i, j = slices.Index(t.Rows, row), slices.Index(t.Columns, column)
+	if i < 0 || j < 0 {
+		return value, false
+	}
+
+	return t.Cells[i][j], t.has(i, j)
+}
+
+// SortRows sorts the rows, together with their cells, according to the less function provided.
+func (t *PivotTable[R, C, V]) SortRows(less func(l R, r R) bool) {
+	var order = t.order(len(t.Rows), func(i, j int) bool { return less(t.Rows[i], t.Rows[j]) })
+
+	if t.present != nil {
+		t.present = t.presence(order, positions(len(t.Columns)))
+	}
+
+	t.Rows = TransformBy(order, func(i int) R { return t.Rows[i] })
+	t.Cells = TransformBy(order, func(i int) []V { return t.Cells[i] })
+}
+
+// SortColumns sorts the columns, together with their cells, according to the less function provided.
+func (t *PivotTable[R, C, V]) SortColumns(less func(l C, r C) bool) {
+	var order = t.order(len(t.Columns), func(i, j int) bool { return less(t.Columns[i], t.Columns[j]) })
+
+	if t.present != nil {
+		t.present = t.presence(positions(len(t.Rows)), order)
+	}
+
+	t.Columns = TransformBy(order, func(j int) C { return t.Columns[j] })
+	for i, cells := range t.Cells {
+		t.Cells[i] = TransformBy(order, func(j int) V { return cells[j] })
+	}
+}
+
+// has reports whether the cell aggregated at least one element. Cells of a table that was not
+// built by Pivot, and cells added to a table after Pivot built it, are considered present.
+func (t *PivotTable[R, C, V]) has(i, j int) bool {
+	return i >= len(t.present) || j >= len(t.present[i]) || t.present[i][j]
+}
+
+// presence returns whether the cells at the rows and columns are present, by position.
+func (t *PivotTable[R, C, V]) presence(rows, columns []int) [][]bool {
+	return TransformBy(rows, func(i int) []bool {
+		return TransformBy(columns, func(j int) bool { return t.has(i, j) })
+	})
+}
+
+// order returns the positions 0..n-1 stably sorted by the less function.
+func (t *PivotTable[R, C, V]) order(n int, less func(i, j int) bool) []int {
+	var result = positions(n)
+	sort.SliceStable(result, func(i, j int) bool { return less(result[i], result[j]) })
+
+	return result
+}
+
+// positions returns the positions 0..n-1.
+func positions(n int) []int {
+	var result = make([]int, n)
+	for i := range result {
+		result[i] = i
+	}
+
+	return result
+}
+
+// Unpivot flattens the table back to records, projecting every cell that aggregated at least
+// one element in row-major order. Cells without elements are skipped, even if the value of a cell
+// with elements equals the missing value.
+func Unpivot[R, C comparable, V, T any](table *PivotTable[R, C, V], project func(row R, column C, value V) T) []T {
+	var result []T
+	for i, row := range table.Rows {
+		for j, column := range table.Columns {
+			if table.has(i, j) {
+				result = append(result, project(row, column, table.Cells[i][j]))
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,140 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type quarterSale struct {
+	Region  string
+	Quarter string
+	Amount  int
+}
+
+func TestPivotAndUnpivot(t *testing.T) {
+	source := []quarterSale{
+		{"us", "Q2", 5},
+		{"eu", "Q1", 10},
+		{"eu", "Q2", 7},
+		{"eu", "Q1", 3},
+		{"apac", "Q3", 1},
+	}
+
+	table := collection.Pivot(source,
+		func(s quarterSale) string { return s.Region },
+		func(s quarterSale) string { return s.Quarter },
+		collection.SumAggregator(func(s quarterSale) int { return s.Amount }),
+		-1,
+	)
+
+	want := &collection.PivotTable[string, string, int]{
+		Rows:    []string{"us", "eu", "apac"},
+		Columns: []string{"Q2", "Q1", "Q3"},
+		Cells:   [][]int{{5, -1, -1}, {7, 13, -1}, {-1, -1, 1}},
+	}
+
+	if !reflect.DeepEqual(table.Rows, want.Rows) || !reflect.DeepEqual(table.Columns, want.Columns) || !reflect.DeepEqual(table.Cells, want.Cells) {
+		t.Fatalf("Pivot() = %v %v %v; want %v %v %v", table.Rows, table.Columns, table.Cells, want.Rows, want.Columns, want.Cells)
+	}
+
+	table.SortRows(func(l, r string) bool { return l < r })
+	table.SortColumns(func(l, r string) bool { return l < r })
+
+	if want := [][]int{{-1, -1, 1}, {13, 7, -1}, {-1, 5, -1}}; !reflect.DeepEqual(table.Cells, want) {
+		t.Errorf("sorted Cells = %v; want %v", table.Cells, want)
+	}
+
+	if v, ok := table.Get("eu", "Q1"); !ok || v != 13 {
+		t.Errorf("Get(eu, Q1) = %v, %v; want 13, true", v, ok)
+	}
+
+	if v, ok := table.Get("us", "Q1"); ok || v != -1 {
+		t.Errorf("Get(us, Q1) = %v, %v; want -1, false", v, ok)
+	}
+
+	records := collection.Unpivot(table, func(region, quarter string, amount int) quarterSale {
+		return quarterSale{Region: region, Quarter: quarter, Amount: amount}
+	})
+
+	if want := []quarterSale{{"apac", "Q3", 1}, {"eu", "Q1", 13}, {"eu", "Q2", 7}, {"us", "Q2", 5}}; !reflect.DeepEqual(records, want) {
+		t.Errorf("Unpivot() = %v; want %v", records, want)
+	}
+}
+
+func TestUnpivotLiteralTable(t *testing.T) {
+	table := &collection.PivotTable[string, int, string]{
+		Rows:    []string{"a", "b"},
+		Columns: []int{1, 2},
+		Cells:   [][]string{{"a1", "a2"}, {"b1", "b2"}},
+	}
+
+	got := collection.Unpivot(table, func(row string, column int, value string) string {
+		return strings.ToUpper(value)
+	})
+
+	if want := []string{"A1", "A2", "B1", "B2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unpivot() = %v; want %v", got, want)
+	}
+}
+
+func TestUnpivotKeepsMissingValues(t *testing.T) {
+	source := []quarterSale{{"eu", "Q1", 0}, {"us", "Q2", 4}}
+
+	table := collection.Pivot(source,
+		func(s quarterSale) string { return s.Region },
+		func(s quarterSale) string { return s.Quarter },
+		collection.SumAggregator(func(s quarterSale) int { return s.Amount }),
+		0,
+	)
+
+	records := collection.Unpivot(table, func(region, quarter string, amount int) quarterSale {
+		return quarterSale{Region: region, Quarter: quarter, Amount: amount}
+	})
+
+	// The eu Q1 sum equals the missing value, but the cell aggregated an element, unlike eu Q2.
+	if want := []quarterSale{{"eu", "Q1", 0}, {"us", "Q2", 4}}; !reflect.DeepEqual(records, want) {
+		t.Errorf("Unpivot() = %v; want %v", records, want)
+	}
+}
+
+func TestPivotTableGrown(t *testing.T) {
+	source := []quarterSale{{"eu", "Q1", 3}, {"us", "Q2", 4}}
+
+	table := collection.Pivot(source,
+		func(s quarterSale) string { return s.Region },
+		func(s quarterSale) string { return s.Quarter },
+		collection.SumAggregator(func(s quarterSale) int { return s.Amount }),
+		0,
+	)
+
+	// Cells added after Pivot built the table are present.
+	table.Columns = append(table.Columns, "Q3")
+	table.Cells[0] = append(table.Cells[0], 1)
+	table.Cells[1] = append(table.Cells[1], 2)
+	table.Rows = append(table.Rows, "apac")
+	table.Cells = append(table.Cells, []int{5, 6, 7})
+
+	if v, ok := table.Get("apac", "Q2"); !ok || v != 6 {
+		t.Errorf("Get(apac, Q2) = %v, %v; want 6, true", v, ok)
+	}
+
+	table.SortRows(func(l, r string) bool { return l < r })
+	table.SortColumns(func(l, r string) bool { return l > r })
+
+	records := collection.Unpivot(table, func(region, quarter string, amount int) quarterSale {
+		return quarterSale{Region: region, Quarter: quarter, Amount: amount}
+	})
+
+	want := []quarterSale{
+		{"apac", "Q3", 7}, {"apac", "Q2", 6}, {"apac", "Q1", 5},
+		{"eu", "Q3", 1}, {"eu", "Q1", 3},
+		{"us", "Q3", 2}, {"us", "Q2", 4},
+	}
+
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Unpivot() = %v; want %v", records, want)
+	}
+}
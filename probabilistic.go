@@ -0,0 +1,438 @@
+package collection
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Hasher maps an item to a 64-bit hash. Probabilistic structures that are merged
+// or serialized must use a hasher that is stable across processes.
+type Hasher[T any] func(T) uint64
+
+// StringHasher returns a stable Hasher for string-like items based on FNV-1a.
+func StringHasher[T ~string]() Hasher[T] {
+	return func(item T) uint64 {
+		var h = fnv.New64a()
+		h.Write([]byte(item))
+		return h.Sum64()
+	}
+}
+
+// BytesHasher returns a stable Hasher for byte slices based on FNV-1a.
+func BytesHasher() Hasher[[]byte] {
+	return func(item []byte) uint64 {
+		var h = fnv.New64a()
+		h.Write(item)
+		return h.Sum64()
+	}
+}
+
+// IntegerHasher returns a stable Hasher for integers.
+func IntegerHasher[T constraints.Integer]() Hasher[T] {
+	return func(item T) uint64 {
+		return mixHash(uint64(item))
+	}
+}
+
+// ComparableHasher returns a Hasher for any comparable items based on hash/maphash.
+// Pointers and channels are hashed by address and interfaces by their dynamic type and value.
+// The hashes are random per hasher, so structures using it can only be merged with
+// structures using the same hasher and can't be decoded by other processes.
+func ComparableHasher[T comparable]() Hasher[T] {
+	var seed = maphash.MakeSeed()
+
+	return func(item T) uint64 {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		writeComparable(&h, reflect.ValueOf(&item).Elem())
+
+		return h.Sum64()
+	}
+}
+
+// writeComparable writes a comparable value to h so that equal values write the same bytes.
+func writeComparable(h *maphash.Hash, v reflect.Value) {
+	var writeUint64 = func(x uint64) {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+
+	// Adding zero turns negative zero, which equals zero, into zero.
+	var writeFloat = func(f float64) {
+		writeUint64(math.Float64bits(f + 0))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint64(1)
+		} else {
+			writeUint64(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		writeUint64(uint64(v.Len()))
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint64(0)
+			return
+		}
+
+		h.WriteString(v.Elem().Type().String())
+		writeComparable(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeComparable(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeComparable(h, v.Field(i))
+		}
+	}
+}
+
+// mixHash is the splitmix64 finalizer. It spreads the entropy of weak hashes,
+// e.g. of consecutive integers, over all bits.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// ErrIncompatibleSketch is returned when merging or decoding structures with different parameters.
+var ErrIncompatibleSketch = errors.New("collection: incompatible sketch parameters")
+
+// BloomFilter is a space-efficient set that answers membership queries with
+// no false negatives and a bounded rate of false positives.
+type BloomFilter[T any] struct {
+	hasher Hasher[T]
+	bits   *BitSet
+	m      uint64
+	k      uint32
+}
+
+// NewBloomFilter returns a BloomFilter sized to hold capacity items with the given false positive rate.
+// It panics if capacity is not positive or falsePositiveRate is not between 0 and 1, exclusive.
+func NewBloomFilter[T any](capacity int, falsePositiveRate float64, hasher Hasher[T]) *BloomFilter[T] {
+	if capacity < 1 || !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		panic(fmt.Sprintf("collection: invalid bloom filter capacity %d or false positive rate %v", capacity, falsePositiveRate))
+	}
+
+	var (
+		n = float64(capacity)
+		m = math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+		k = math.Max(math.Round(m/n*math.Ln2), 1)
+	)
+
+	return &BloomFilter[T]{hasher: hasher, bits: &BitSet{}, m: uint64(math.Max(m, 1)), k: uint32(k)}
+}
+
+// Add adds the item to the filter.
+func (b *BloomFilter[T]) Add(item T) {
+	var h1, h2 = doubleHash(b.hasher(item))
+	for i := uint64(0); i < uint64(b.k); i++ {
+		b.bits.Set(uint((h1 + i*h2) % b.m))
+	}
+}
+
+// Contains reports whether the item may have been added. False means it definitely was not.
+func (b *BloomFilter[T]) Contains(item T) bool {
+	var h1, h2 = doubleHash(b.hasher(item))
+	for i := uint64(0); i < uint64(b.k); i++ {
+		if !b.bits.Test(uint((h1 + i*h2) % b.m)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Merge adds all items of other to the filter. Both filters must have the same size and number of hashes.
+func (b *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if b.m != other.m || b.k != other.k {
+		return ErrIncompatibleSketch
+	}
+
+	b.bits = b.bits.Or(other.bits)
+
+	return nil
+}
+
+// MarshalBinary encodes the filter parameters and bits. The hasher is not encoded.
+func (b *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	var words, _ = b.bits.MarshalBinary()
+
+	var data = binary.LittleEndian.AppendUint64(nil, b.m)
+	data = binary.LittleEndian.AppendUint32(data, b.k)
+
+	return append(data, words...), nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary, keeping the hasher of the receiver.
+func (b *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return ErrIncompatibleSketch
+	}
+
+	var (
+		m = binary.LittleEndian.Uint64(data)
+		k = binary.LittleEndian.Uint32(data[8:])
+	)
+
+	if m == 0 || k == 0 {
+		return ErrIncompatibleSketch
+	}
+
+	var set = &BitSet{}
+	if err := set.UnmarshalBinary(data[12:]); err != nil {
+		return err
+	}
+
+	b.m, b.k, b.bits = m, k, set
+
+	return nil
+}
+
+// CountMinSketch estimates item frequencies in sublinear space. Estimates never undercount
+// and overcount by at most epsilon times the total count with probability 1 - delta.
+type CountMinSketch[T any] struct {
+	hasher   Hasher[T]
+	width    uint64
+	depth    uint64
+	counters []uint64
+	total    uint64
+}
+
+// NewCountMinSketch returns a CountMinSketch with the given error bound and failure probability.
+// It panics if epsilon or delta is not between 0 and 1, exclusive.
+func NewCountMinSketch[T any](epsilon, delta float64, hasher Hasher[T]) *CountMinSketch[T] {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		panic(fmt.Sprintf("collection: invalid count-min sketch epsilon %v or delta %v", epsilon, delta))
+	}
+
+	var (
+		width = uint64(math.Max(math.Ceil(math.E/epsilon), 1))
+		depth = uint64(math.Max(math.Ceil(math.Log(1/delta)), 1))
+	)
+
+	return &CountMinSketch[T]{hasher: hasher, width: width, depth: depth, counters: make([]uint64, width*depth)}
+}
+
+// Add increments the count of the item by one.
+func (c *CountMinSketch[T]) Add(item T) {
+	c.AddN(item, 1)
+}
+
+// AddN increments the count of the item by n.
+func (c *CountMinSketch[T]) AddN(item T, n uint64) {
+	var h1, h2 = doubleHash(c.hasher(item))
+	for row := uint64(0); row < c.depth; row++ {
+		c.counters[row*c.width+(h1+row*h2)%c.width] += n
+	}
+
+	c.total += n
+}
+
+// Count returns the estimated count of the item.
+func (c *CountMinSketch[T]) Count(item T) uint64 {
+	var (
+		h1, h2 = doubleHash(c.hasher(item))
+		result = uint64(math.MaxUint64)
+	)
+
+	for row := uint64(0); row < c.depth; row++ {
+		result = Min(result, c.counters[row*c.width+(h1+row*h2)%c.width])
+	}
+
+	return result
+}
+
+// Total returns the sum of all counts.
+func (c *CountMinSketch[T]) Total() uint64 {
+	return c.total
+}
+
+// Merge adds the counts of other to the sketch. Both sketches must have the same dimensions.
+func (c *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if c.width != other.width || c.depth != other.depth {
+		return ErrIncompatibleSketch
+	}
+
+	for i, v := range other.counters {
+		c.counters[i] += v
+	}
+
+	c.total += other.total
+
+	return nil
+}
+
+// MarshalBinary encodes the sketch dimensions and counters. The hasher is not encoded.
+func (c *CountMinSketch[T]) MarshalBinary() ([]byte, error) {
+	var data = make([]byte, 0, 24+8*len(c.counters))
+	data = binary.LittleEndian.AppendUint64(data, c.width)
+	data = binary.LittleEndian.AppendUint64(data, c.depth)
+	data = binary.LittleEndian.AppendUint64(data, c.total)
+
+	for _, v := range c.counters {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary, keeping the hasher of the receiver.
+func (c *CountMinSketch[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 24 {
+		return ErrIncompatibleSketch
+	}
+
+	var (
+		width = binary.LittleEndian.Uint64(data)
+		depth = binary.LittleEndian.Uint64(data[8:])
+		total = binary.LittleEndian.Uint64(data[16:])
+	)
+
+	data = data[24:]
+
+	if width == 0 || depth == 0 || uint64(len(data)) != width*depth*8 || uint64(len(data))/8/width != depth {
+		return ErrIncompatibleSketch
+	}
+
+	var counters = make([]uint64, width*depth)
+	for i := range counters {
+		counters[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+
+	c.width, c.depth, c.total, c.counters = width, depth, total, counters
+
+	return nil
+}
+
+const (
+	hyperLogLogMinPrecision = 4
+	hyperLogLogMaxPrecision = 18
+)
+
+// HyperLogLog estimates the number of distinct items using 2^precision one-byte registers,
+// with a standard error of about 1.04 / sqrt(2^precision).
+type HyperLogLog[T any] struct {
+	hasher    Hasher[T]
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns a HyperLogLog with the given precision, clamped to [4, 18].
+func NewHyperLogLog[T any](precision uint8, hasher Hasher[T]) *HyperLogLog[T] {
+	precision = Min(Max(precision, hyperLogLogMinPrecision), hyperLogLogMaxPrecision)
+
+	return &HyperLogLog[T]{hasher: hasher, precision: precision, registers: make([]uint8, 1<<precision)}
+}
+
+// Add adds the item to the estimated set.
+func (h *HyperLogLog[T]) Add(item T) {
+	var (
+		hash  = mixHash(h.hasher(item))
+		index = hash >> (64 - h.precision)
+		rank  = uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+	)
+
+	h.registers[index] = Max(h.registers[index], rank)
+}
+
+// Count returns the estimated number of distinct items added.
+func (h *HyperLogLog[T]) Count() uint64 {
+	var (
+		m     = float64(len(h.registers))
+		sum   float64
+		zeros int
+	)
+
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var estimate = hyperLogLogAlpha(m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Merge combines the registers of other into h, estimating the union of both sets.
+// Both must have the same precision.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.precision != other.precision {
+		return ErrIncompatibleSketch
+	}
+
+	for i, r := range other.registers {
+		h.registers[i] = Max(h.registers[i], r)
+	}
+
+	return nil
+}
+
+// MarshalBinary encodes the precision and registers. The hasher is not encoded.
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	return append([]byte{h.precision}, h.registers...), nil
+}
+
+// UnmarshalBinary decodes a HyperLogLog encoded by MarshalBinary, keeping the hasher of the receiver.
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] < hyperLogLogMinPrecision || data[0] > hyperLogLogMaxPrecision || len(data)-1 != 1<<data[0] {
+		return ErrIncompatibleSketch
+	}
+
+	h.precision = data[0]
+	h.registers = Clone(data[1:])
+
+	return nil
+}
+
+func hyperLogLogAlpha(m float64) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+
+	return 0.7213 / (1 + 1.079/m)
+}
+
+// doubleHash derives two hashes for Kirsch-Mitzenmacher double hashing,
+// the second one odd so it never degenerates to a single probe.
+func doubleHash(h uint64) (uint64, uint64) {
+	var mixed = mixHash(h)
+	return mixed, mixHash(mixed) | 1
+}
@@ -0,0 +1,225 @@
+package collection_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10_000
+
+	b := collection.NewBloomFilter(n, 0.01, collection.IntegerHasher[int]())
+	for i := 0; i < n; i++ {
+		b.Add(i)
+	}
+
+	for i := 0; i < n; i++ {
+		if !b.Contains(i) {
+			t.Fatalf("Contains(%v) = false for an added item", i)
+		}
+	}
+
+	var falsePositives int
+	for i := n; i < 2*n; i++ {
+		if b.Contains(i) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("false positive rate = %v; want about 0.01", rate)
+	}
+}
+
+func TestBloomFilterMergeAndEncoding(t *testing.T) {
+	hasher := collection.StringHasher[string]()
+
+	shardA := collection.NewBloomFilter(100, 0.01, hasher)
+	shardB := collection.NewBloomFilter(100, 0.01, hasher)
+	shardA.Add("alice")
+	shardB.Add("bob")
+
+	if err := shardA.Merge(shardB); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	data, err := shardA.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	decoded := collection.NewBloomFilter(1, 0.5, hasher)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	if !decoded.Contains("alice") || !decoded.Contains("bob") {
+		t.Errorf("decoded filter lost merged items")
+	}
+
+	if err := shardA.Merge(collection.NewBloomFilter(1000, 0.01, hasher)); !errors.Is(err, collection.ErrIncompatibleSketch) {
+		t.Errorf("Merge() of different sizes error = %v", err)
+	}
+
+	if err := decoded.UnmarshalBinary(make([]byte, 12)); !errors.Is(err, collection.ErrIncompatibleSketch) {
+		t.Errorf("UnmarshalBinary() of zero parameters error = %v", err)
+	}
+
+	if !decoded.Contains("alice") {
+		t.Errorf("failed UnmarshalBinary() modified the filter")
+	}
+}
+
+func TestComparableHasher(t *testing.T) {
+	type key struct {
+		Name  string
+		Shard any
+		Score float64
+	}
+
+	hasher := collection.ComparableHasher[key]()
+
+	b := collection.NewBloomFilter(100, 0.01, hasher)
+	b.Add(key{Name: "alice", Shard: 1})
+	b.Add(key{Name: "bob", Shard: "eu", Score: math.Copysign(0, -1)})
+
+	for _, item := range []key{{Name: "alice", Shard: 1}, {Name: "bob", Shard: "eu"}} {
+		if !b.Contains(item) {
+			t.Errorf("Contains(%v) = false for an added item", item)
+		}
+	}
+
+	if hasher(key{Shard: 1}) == hasher(key{Shard: int64(1)}) {
+		t.Errorf("hasher ignores the dynamic type of interfaces")
+	}
+}
+
+func TestSketchParameters(t *testing.T) {
+	hasher := collection.IntegerHasher[int]()
+
+	cases := []struct {
+		name string
+		new  func()
+	}{
+		{name: "bloom zero capacity", new: func() { collection.NewBloomFilter(0, 0.01, hasher) }},
+		{name: "bloom zero rate", new: func() { collection.NewBloomFilter(100, 0, hasher) }},
+		{name: "bloom rate of one", new: func() { collection.NewBloomFilter(100, 1, hasher) }},
+		{name: "bloom NaN rate", new: func() { collection.NewBloomFilter(100, math.NaN(), hasher) }},
+		{name: "count-min zero epsilon", new: func() { collection.NewCountMinSketch(0, 0.01, hasher) }},
+		{name: "count-min delta of one", new: func() { collection.NewCountMinSketch(0.01, 1, hasher) }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("constructor did not panic")
+				}
+			}()
+
+			tc.new()
+		})
+	}
+}
+
+func TestCountMinSketch(t *testing.T) {
+	const epsilon = 0.001
+
+	c := collection.NewCountMinSketch(epsilon, 0.01, collection.StringHasher[string]())
+
+	exact := make(map[string]uint64)
+	for i := 0; i < 20_000; i++ {
+		key := fmt.Sprintf("user-%d", i%(1+i%500))
+		c.Add(key)
+		exact[key]++
+	}
+
+	c.AddN("heavy", 5000)
+	exact["heavy"] += 5000
+
+	bound := uint64(epsilon * float64(c.Total()))
+	for key, want := range exact {
+		got := c.Count(key)
+		if got < want || got > want+bound {
+			t.Errorf("Count(%v) = %v; want within [%v, %v]", key, got, want, want+bound)
+		}
+	}
+}
+
+func TestCountMinSketchMergeAndEncoding(t *testing.T) {
+	hasher := collection.IntegerHasher[uint32]()
+
+	a := collection.NewCountMinSketch(0.01, 0.01, hasher)
+	b := collection.NewCountMinSketch(0.01, 0.01, hasher)
+	a.AddN(7, 3)
+	b.AddN(7, 4)
+
+	if err := a.Merge(b); err != nil || a.Count(7) < 7 || a.Total() != 7 {
+		t.Fatalf("Merge() = %v, Count(7) = %v, Total() = %v", err, a.Count(7), a.Total())
+	}
+
+	data, _ := a.MarshalBinary()
+
+	decoded := collection.NewCountMinSketch(0.5, 0.5, hasher)
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.Count(7) != a.Count(7) || decoded.Total() != 7 {
+		t.Errorf("UnmarshalBinary() = %v, Count(7) = %v", err, decoded.Count(7))
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, collection.ErrIncompatibleSketch) {
+		t.Errorf("UnmarshalBinary() of truncated data error = %v", err)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	cases := []int{0, 10, 1000, 100_000}
+
+	for _, n := range cases {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			h := collection.NewHyperLogLog(14, collection.IntegerHasher[int]())
+			for i := 0; i < n; i++ {
+				h.Add(i)
+				h.Add(i)
+			}
+
+			got := float64(h.Count())
+			if math.Abs(got-float64(n)) > math.Max(float64(n)*0.03, 1) {
+				t.Errorf("Count() = %v; want about %v", got, n)
+			}
+		})
+	}
+}
+
+func TestHyperLogLogMergeAndEncoding(t *testing.T) {
+	hasher := collection.StringHasher[string]()
+
+	a := collection.NewHyperLogLog(12, hasher)
+	b := collection.NewHyperLogLog(12, hasher)
+	for i := 0; i < 5000; i++ {
+		a.Add(fmt.Sprint("a", i))
+		b.Add(fmt.Sprint("b", i))
+		b.Add(fmt.Sprint("a", i))
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if got := float64(a.Count()); math.Abs(got-10_000) > 10_000*0.05 {
+		t.Errorf("Count() after Merge = %v; want about 10000", got)
+	}
+
+	data, _ := a.MarshalBinary()
+
+	decoded := collection.NewHyperLogLog(4, hasher)
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.Count() != a.Count() {
+		t.Errorf("UnmarshalBinary() = %v, Count() = %v; want %v", err, decoded.Count(), a.Count())
+	}
+
+	if err := a.Merge(collection.NewHyperLogLog(10, hasher)); !errors.Is(err, collection.ErrIncompatibleSketch) {
+		t.Errorf("Merge() of different precision error = %v", err)
+	}
+}
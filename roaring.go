@@ -0,0 +1,496 @@
+package collection
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/bits"
+	"sort"
+)
+
+const (
+	roaringArrayMax    = 4096
+	roaringBitmapWords = 1 << 16 / wordSize
+)
+
+// roaringContainer holds the low 16 bits of the values sharing the same high 16 bits.
+// Sparse containers are sorted arrays, dense ones are bitmaps.
+type roaringContainer struct {
+	array  []uint16
+	bitmap []uint64
+	card   int
+}
+
+// RoaringBitmap is a compressed set of uint32 values suited for sparse large ID spaces.
+// Values are split by their high 16 bits into containers that are stored either as
+// sorted arrays or as bitmaps, whichever is smaller. The zero value is an empty set ready to use.
+type RoaringBitmap struct {
+	keys       []uint16
+	containers []*roaringContainer
+}
+
+// NewRoaringBitmap returns a RoaringBitmap holding the given values.
+func NewRoaringBitmap(values ...uint32) *RoaringBitmap {
+	var result = &RoaringBitmap{}
+	for _, v := range values {
+		result.Add(v)
+	}
+
+	return result
+}
+
+// Add adds the value to the set.
+func (r *RoaringBitmap) Add(x uint32) {
+	var key, low = uint16(x >> 16), uint16(x)
+
+	var i, ok = r.search(key)
+	if !ok {
+		r.keys = append(r.keys, 0)
+		copy(r.keys[i+1:], r.keys[i:])
+		r.keys[i] = key
+
+		r.containers = append(r.containers, nil)
+		copy(r.containers[i+1:], r.containers[i:])
+		r.containers[i] = &roaringContainer{}
+	}
+
+	r.containers[i].add(low)
+}
+
+// Remove removes the value from the set.
+func (r *RoaringBitmap) Remove(x uint32) {
+	var i, ok = r.search(uint16(x >> 16))
+	if !ok {
+		return
+	}
+
+	var c = r.containers[i]
+	c.remove(uint16(x))
+
+	if c.card == 0 {
+		r.keys = append(r.keys[:i], r.keys[i+1:]...)
+		r.containers = append(r.containers[:i], r.containers[i+1:]...)
+	}
+}
+
+// Contains reports whether the value is in the set.
+func (r *RoaringBitmap) Contains(x uint32) bool {
+	var i, ok = r.search(uint16(x >> 16))
+	return ok && r.containers[i].contains(uint16(x))
+}
+
+// Cardinality returns the number of values in the set.
+func (r *RoaringBitmap) Cardinality() int {
+	var count int
+	for _, c := range r.containers {
+		count += c.card
+	}
+
+	return count
+}
+
+// Each calls do for each value in ascending order. If do returns false, the iteration stops.
+func (r *RoaringBitmap) Each(do func(x uint32) bool) {
+	for i, c := range r.containers {
+		var high = uint32(r.keys[i]) << 16
+		if !c.each(func(low uint16) bool { return do(high | uint32(low)) }) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the values in ascending order.
+func (r *RoaringBitmap) ToSlice() []uint32 {
+	var result = make([]uint32, 0, r.Cardinality())
+	r.Each(func(x uint32) bool {
+		result = append(result, x)
+		return true
+	})
+
+	return result
+}
+
+// Clone returns a copy of the set.
+func (r *RoaringBitmap) Clone() *RoaringBitmap {
+	return &RoaringBitmap{
+		keys:       Clone(r.keys),
+		containers: TransformBy(r.containers, (*roaringContainer).clone),
+	}
+}
+
+// Equal reports whether both sets hold the same values.
+func (r *RoaringBitmap) Equal(other *RoaringBitmap) bool {
+	return Equal(r.keys, other.keys) && EqualFunc(r.containers, other.containers, (*roaringContainer).equal)
+}
+
+// And returns a new set with the values present in both r and other.
+func (r *RoaringBitmap) And(other *RoaringBitmap) *RoaringBitmap {
+	return r.combine(other, roaringOp{
+		words:  func(l, r uint64) uint64 { return l & r },
+		member: func(l, r bool) bool { return l && r },
+	})
+}
+
+// Or returns a new set with the values present in r or other.
+func (r *RoaringBitmap) Or(other *RoaringBitmap) *RoaringBitmap {
+	return r.combine(other, roaringOp{
+		words:  func(l, r uint64) uint64 { return l | r },
+		member: func(l, r bool) bool { return l || r },
+	})
+}
+
+// Xor returns a new set with the values present in exactly one of r and other.
+func (r *RoaringBitmap) Xor(other *RoaringBitmap) *RoaringBitmap {
+	return r.combine(other, roaringOp{
+		words:  func(l, r uint64) uint64 { return l ^ r },
+		member: func(l, r bool) bool { return l != r },
+	})
+}
+
+// AndNot returns a new set with the values present in r but not in other.
+func (r *RoaringBitmap) AndNot(other *RoaringBitmap) *RoaringBitmap {
+	return r.combine(other, roaringOp{
+		words:  func(l, r uint64) uint64 { return l &^ r },
+		member: func(l, r bool) bool { return l && !r },
+	})
+}
+
+type roaringOp struct {
+	words  func(l, r uint64) uint64
+	member func(l, r bool) bool
+}
+
+func (r *RoaringBitmap) combine(other *RoaringBitmap, op roaringOp) *RoaringBitmap {
+	var (
+		result = &RoaringBitmap{}
+		i, j   int
+	)
+
+	var push = func(key uint16, c *roaringContainer) {
+		if c != nil && c.card > 0 {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	for i < len(r.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || i < len(r.keys) && r.keys[i] < other.keys[j]:
+			if op.member(true, false) {
+				push(r.keys[i], r.containers[i].clone())
+			}
+			i++
+		case i == len(r.keys) || other.keys[j] < r.keys[i]:
+			if op.member(false, true) {
+				push(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			push(r.keys[i], r.containers[i].combine(other.containers[j], op))
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+func (r *RoaringBitmap) search(key uint16) (int, bool) {
+	var i = sort.Search(len(r.keys), func(i int) bool { return r.keys[i] >= key })
+	return i, i < len(r.keys) && r.keys[i] == key
+}
+
+// MarshalBinary encodes the set as a sequence of containers:
+// [container count uint32] then for each [key uint16][cardinality uint32][values],
+// where values are uint16 array entries up to 4096 values and 1024 bitmap words above it.
+func (r *RoaringBitmap) MarshalBinary() ([]byte, error) {
+	var data = binary.LittleEndian.AppendUint32(nil, uint32(len(r.keys)))
+	for i, c := range r.containers {
+		data = binary.LittleEndian.AppendUint16(data, r.keys[i])
+		data = binary.LittleEndian.AppendUint32(data, uint32(c.card))
+
+		if c.bitmap == nil {
+			for _, v := range c.array {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		} else {
+			for _, w := range c.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, w)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a set encoded by MarshalBinary.
+func (r *RoaringBitmap) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errBitSetData
+	}
+
+	var (
+		count  = int(binary.LittleEndian.Uint32(data))
+		result RoaringBitmap
+	)
+
+	data = data[4:]
+
+	for i := 0; i < count; i++ {
+		if len(data) < 6 {
+			return errBitSetData
+		}
+
+		var (
+			key  = binary.LittleEndian.Uint16(data)
+			card = int(binary.LittleEndian.Uint32(data[2:]))
+			c    = &roaringContainer{card: card}
+		)
+
+		data = data[6:]
+
+		if card == 0 || card > 1<<16 || len(result.keys) > 0 && key <= result.keys[len(result.keys)-1] {
+			return errBitSetData
+		}
+
+		if card <= roaringArrayMax {
+			if len(data) < card*2 {
+				return errBitSetData
+			}
+
+			c.array = make([]uint16, card)
+			for j := range c.array {
+				c.array[j] = binary.LittleEndian.Uint16(data[j*2:])
+				if j > 0 && c.array[j] <= c.array[j-1] {
+					return errBitSetData
+				}
+			}
+
+			data = data[card*2:]
+		} else {
+			if len(data) < roaringBitmapWords*8 {
+				return errBitSetData
+			}
+
+			var set int
+
+			c.bitmap = make([]uint64, roaringBitmapWords)
+			for j := range c.bitmap {
+				c.bitmap[j] = binary.LittleEndian.Uint64(data[j*8:])
+				set += bits.OnesCount64(c.bitmap[j])
+			}
+
+			if set != card {
+				return errBitSetData
+			}
+
+			data = data[roaringBitmapWords*8:]
+		}
+
+		result.keys = append(result.keys, key)
+		result.containers = append(result.containers, c)
+	}
+
+	if len(data) != 0 {
+		return errBitSetData
+	}
+
+	*r = result
+
+	return nil
+}
+
+// MarshalJSON encodes the set as an ascending array of values.
+func (r *RoaringBitmap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.ToSlice())
+}
+
+// UnmarshalJSON decodes an array of values.
+func (r *RoaringBitmap) UnmarshalJSON(data []byte) error {
+	var values []uint32
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*r = *NewRoaringBitmap(values...)
+
+	return nil
+}
+
+func (c *roaringContainer) add(low uint16) {
+	if c.bitmap != nil {
+		if c.bitmap[low/wordSize]&(1<<(low%wordSize)) == 0 {
+			c.bitmap[low/wordSize] |= 1 << (low % wordSize)
+			c.card++
+		}
+
+		return
+	}
+
+	var i, ok = c.searchArray(low)
+	if ok {
+		return
+	}
+
+	c.array = append(c.array, 0)
+	copy(c.array[i+1:], c.array[i:])
+	c.array[i] = low
+	c.card++
+
+	if c.card > roaringArrayMax {
+		c.toBitmap()
+	}
+}
+
+func (c *roaringContainer) remove(low uint16) {
+	if c.bitmap != nil {
+		if c.bitmap[low/wordSize]&(1<<(low%wordSize)) != 0 {
+			c.bitmap[low/wordSize] &^= 1 << (low % wordSize)
+			c.card--
+		}
+
+		if c.card <= roaringArrayMax {
+			c.toArray()
+		}
+
+		return
+	}
+
+	if i, ok := c.searchArray(low); ok {
+		c.array = append(c.array[:i], c.array[i+1:]...)
+		c.card--
+	}
+}
+
+func (c *roaringContainer) contains(low uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[low/wordSize]&(1<<(low%wordSize)) != 0
+	}
+
+	var _, ok = c.searchArray(low)
+	return ok
+}
+
+func (c *roaringContainer) each(do func(low uint16) bool) bool {
+	if c.bitmap == nil {
+		for _, v := range c.array {
+			if !do(v) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for i, w := range c.bitmap {
+		for w != 0 {
+			var bit = bits.TrailingZeros64(w)
+			if !do(uint16(i*wordSize + bit)) {
+				return false
+			}
+
+			w &= w - 1
+		}
+	}
+
+	return true
+}
+
+func (c *roaringContainer) combine(other *roaringContainer, op roaringOp) *roaringContainer {
+	if c.bitmap == nil && other.bitmap == nil {
+		return c.mergeArrays(other, op)
+	}
+
+	var (
+		l, r   = c.words(), other.words()
+		result = &roaringContainer{bitmap: make([]uint64, roaringBitmapWords)}
+	)
+
+	for i := range result.bitmap {
+		result.bitmap[i] = op.words(l[i], r[i])
+		result.card += bits.OnesCount64(result.bitmap[i])
+	}
+
+	if result.card <= roaringArrayMax {
+		result.toArray()
+	}
+
+	return result
+}
+
+func (c *roaringContainer) mergeArrays(other *roaringContainer, op roaringOp) *roaringContainer {
+	var (
+		result = &roaringContainer{}
+		i, j   int
+	)
+
+	for i < len(c.array) || j < len(other.array) {
+		var (
+			v   uint16
+			inL = i < len(c.array) && (j == len(other.array) || c.array[i] <= other.array[j])
+			inR = j < len(other.array) && (i == len(c.array) || other.array[j] <= c.array[i])
+		)
+
+		if inL {
+			v = c.array[i]
+			i++
+		}
+
+		if inR {
+			v = other.array[j]
+			j++
+		}
+
+		if op.member(inL, inR) {
+			result.array = append(result.array, v)
+		}
+	}
+
+	result.card = len(result.array)
+	if result.card > roaringArrayMax {
+		result.toBitmap()
+	}
+
+	return result
+}
+
+func (c *roaringContainer) searchArray(low uint16) (int, bool) {
+	var i = sort.Search(len(c.array), func(i int) bool { return c.array[i] >= low })
+	return i, i < len(c.array) && c.array[i] == low
+}
+
+func (c *roaringContainer) words() []uint64 {
+	if c.bitmap != nil {
+		return c.bitmap
+	}
+
+	var words = make([]uint64, roaringBitmapWords)
+	for _, v := range c.array {
+		words[v/wordSize] |= 1 << (v % wordSize)
+	}
+
+	return words
+}
+
+func (c *roaringContainer) toBitmap() {
+	c.bitmap = c.words()
+	c.array = nil
+}
+
+func (c *roaringContainer) toArray() {
+	var array = make([]uint16, 0, c.card)
+	c.each(func(low uint16) bool {
+		array = append(array, low)
+		return true
+	})
+
+	c.array = array
+	c.bitmap = nil
+}
+
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{array: Clone(c.array), bitmap: Clone(c.bitmap), card: c.card}
+}
+
+func (c *roaringContainer) equal(other *roaringContainer) bool {
+	return c.card == other.card && Equal(c.array, other.array) && Equal(c.bitmap, other.bitmap)
+}
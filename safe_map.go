@@ -1,7 +1,10 @@
 package collection
 
 import (
+	"slices"
 	"sync"
+
+	"golang.org/x/exp/constraints"
 )
 
 type SafeMap[K comparable, V any] struct {
@@ -78,3 +81,52 @@ func (s *SafeMap[K, V]) ForEach(fn func(K, V)) {
 		fn(k, v)
 	}
 }
+
+// Snapshot returns a point-in-time copy of the map contents.
+func (s *SafeMap[K, V]) Snapshot() map[K]V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := make(map[K]V, len(s.m))
+	for k, v := range s.m {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+// ForEachSnapshot calls fn for each key-value pair of a snapshot of the map.
+// Unlike ForEach, no lock is held while fn runs, so fn may modify the map.
+func (s *SafeMap[K, V]) ForEachSnapshot(fn func(K, V)) {
+	for k, v := range s.Snapshot() {
+		fn(k, v)
+	}
+}
+
+// Range calls fn for each key-value pair of a snapshot of the map.
+// If fn returns false, range stops the iteration.
+func (s *SafeMap[K, V]) Range(fn func(key K, value V) bool) {
+	for k, v := range s.Snapshot() {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// SafeMapSortedKeys returns the keys of the map in ascending order.
+func SafeMapSortedKeys[K constraints.Ordered, V any](s *SafeMap[K, V]) []K {
+	keys := s.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// SafeMapSortedRange calls fn for each key-value pair of a snapshot of the map in ascending key order.
+// If fn returns false, range stops the iteration.
+func SafeMapSortedRange[K constraints.Ordered, V any](s *SafeMap[K, V], fn func(key K, value V) bool) {
+	snapshot := s.Snapshot()
+	keys := MapKeys(snapshot)
+	slices.Sort(keys)
+	for _, k := range keys {
+		if !fn(k, snapshot[k]) {
+			return
+		}
+	}
+}
@@ -145,3 +145,78 @@ func compareResults(a, b interface{}) bool {
 		return a == b
 	}
 }
+
+func TestSafeMapSnapshot(t *testing.T) {
+	m := NewSafeMap[string, int]()
+	m.Set("k1", 1)
+	m.Set("k2", 2)
+
+	snapshot := m.Snapshot()
+	m.Set("k3", 3)
+	snapshot["k1"] = 100
+
+	if !reflect.DeepEqual(snapshot, map[string]int{"k1": 100, "k2": 2}) {
+		t.Errorf("Snapshot() = %v; want independent copy", snapshot)
+	}
+
+	if v, _ := m.Get("k1"); v != 1 {
+		t.Errorf("Get(k1) = %v; want 1", v)
+	}
+}
+
+func TestSafeMapForEachSnapshotAllowsWrites(t *testing.T) {
+	m := NewSafeMap[string, int]()
+	m.Set("k1", 1)
+	m.Set("k2", 2)
+
+	m.ForEachSnapshot(func(k string, v int) {
+		m.Set(k+"_copy", v)
+	})
+
+	if m.Len() != 4 {
+		t.Errorf("Len() = %v; want 4", m.Len())
+	}
+}
+
+func TestSafeMapRange(t *testing.T) {
+	m := NewSafeMap[int, int]()
+	for i := 0; i < 10; i++ {
+		m.Set(i, i)
+	}
+
+	var visited int
+	m.Range(func(k int, v int) bool {
+		m.Delete(k)
+		visited++
+		return visited < 3
+	})
+
+	if visited != 3 {
+		t.Errorf("Range visited %v entries; want 3", visited)
+	}
+
+	if m.Len() != 7 {
+		t.Errorf("Len() = %v; want 7", m.Len())
+	}
+}
+
+func TestSafeMapSorted(t *testing.T) {
+	m := NewSafeMap[string, int]()
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	if keys := SafeMapSortedKeys(m); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("SafeMapSortedKeys() = %v; want [a b c]", keys)
+	}
+
+	var values []int
+	SafeMapSortedRange(m, func(k string, v int) bool {
+		values = append(values, v)
+		return k != "b"
+	})
+
+	if !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("SafeMapSortedRange() visited %v; want [1 2]", values)
+	}
+}
@@ -0,0 +1,300 @@
+package collection
+
+import (
+	"cmp"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+)
+
+const skipListMaxLevel = 32
+
+// SkipList is a sorted map safe for concurrent use. It implements the lazy skip list
+// of Herlihy et al.: lookups and iteration take no locks, while writers only lock the
+// nodes around the modified position, so writers to different parts of the list don't contend.
+// Iteration is weakly consistent: it reflects some, but not necessarily all, concurrent changes.
+type SkipList[K any, V any] struct {
+	compare func(a, b K) int
+	head    *skipListNode[K, V]
+	size    atomic.Int64
+}
+
+type skipListNode[K any, V any] struct {
+	key         K
+	value       atomic.Pointer[V]
+	next        []atomic.Pointer[skipListNode[K, V]]
+	mu          sync.Mutex
+	marked      atomic.Bool
+	fullyLinked atomic.Bool
+}
+
+// NewSkipList returns an empty SkipList ordered by the compare function,
+// which returns a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewSkipList[K any, V any](compare func(a, b K) int) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		compare: compare,
+		head:    &skipListNode[K, V]{next: make([]atomic.Pointer[skipListNode[K, V]], skipListMaxLevel)},
+	}
+}
+
+// NewOrderedSkipList returns an empty SkipList ordered by the natural order of the keys.
+func NewOrderedSkipList[K constraints.Ordered, V any]() *SkipList[K, V] {
+	return NewSkipList[K, V](cmp.Compare[K])
+}
+
+// Len returns the number of entries.
+func (s *SkipList[K, V]) Len() int {
+	return int(s.size.Load())
+}
+
+// Set stores the value for a key and reports whether the key was inserted rather than updated.
+func (s *SkipList[K, V]) Set(key K, value V) (inserted bool) {
+	var (
+		topLevel     = randomSkipListLevel()
+		preds, succs [skipListMaxLevel]*skipListNode[K, V]
+	)
+
+	for {
+		if found := s.find(key, &preds, &succs); found >= 0 {
+			var node = succs[found]
+			if node.marked.Load() {
+				continue
+			}
+
+			for !node.fullyLinked.Load() {
+				runtime.Gosched()
+			}
+
+			node.value.Store(&value)
+			return false
+		}
+
+		var unlock, valid = s.lockPredecessors(topLevel, &preds, func(level int, pred *skipListNode[K, V]) bool {
+			var succ = succs[level]
+			return !pred.marked.Load() && (succ == nil || !succ.marked.Load()) && pred.next[level].Load() == succ
+		})
+
+		if !valid {
+			unlock()
+			continue
+		}
+
+		var node = &skipListNode[K, V]{key: key, next: make([]atomic.Pointer[skipListNode[K, V]], topLevel)}
+		node.value.Store(&value)
+
+		for level := 0; level < topLevel; level++ {
+			node.next[level].Store(succs[level])
+		}
+
+		for level := 0; level < topLevel; level++ {
+			preds[level].next[level].Store(node)
+		}
+
+		node.fullyLinked.Store(true)
+		s.size.Add(1)
+		unlock()
+
+		return true
+	}
+}
+
+// Get returns the value stored for a key.
+func (s *SkipList[K, V]) Get(key K) (value V, ok bool) {
+	var node = s.lookup(key)
+	if node == nil {
+		return value, false
+	}
+
+	return *node.value.Load(), true
+}
+
+// Has reports whether the key is present.
+func (s *SkipList[K, V]) Has(key K) bool {
+	return s.lookup(key) != nil
+}
+
+// Delete removes the key and reports whether it was present.
+func (s *SkipList[K, V]) Delete(key K) (deleted bool) {
+	var (
+		victim       *skipListNode[K, V]
+		preds, succs [skipListMaxLevel]*skipListNode[K, V]
+	)
+
+	for {
+		var found = s.find(key, &preds, &succs)
+
+		if victim == nil {
+			if found < 0 {
+				return false
+			}
+
+			var node = succs[found]
+			if !node.fullyLinked.Load() || node.marked.Load() || len(node.next)-1 != found {
+				return false
+			}
+
+			node.mu.Lock()
+			if node.marked.Load() {
+				node.mu.Unlock()
+				return false
+			}
+
+			node.marked.Store(true)
+			victim = node
+		}
+
+		var unlock, valid = s.lockPredecessors(len(victim.next), &preds, func(level int, pred *skipListNode[K, V]) bool {
+			return !pred.marked.Load() && pred.next[level].Load() == victim
+		})
+
+		if !valid {
+			unlock()
+			continue
+		}
+
+		for level := len(victim.next) - 1; level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+
+		victim.mu.Unlock()
+		s.size.Add(-1)
+		unlock()
+
+		return true
+	}
+}
+
+// Range calls fn for each entry in ascending key order. If fn returns false, range stops the iteration.
+func (s *SkipList[K, V]) Range(fn func(key K, value V) bool) {
+	s.ascend(s.head.next[0].Load(), func(node *skipListNode[K, V]) bool {
+		return fn(node.key, *node.value.Load())
+	})
+}
+
+// Between calls fn for each entry with from <= key < to in ascending key order.
+// If fn returns false, the iteration stops.
+func (s *SkipList[K, V]) Between(from, to K, fn func(key K, value V) bool) {
+	s.ascend(s.ceilingNode(from), func(node *skipListNode[K, V]) bool {
+		return s.compare(node.key, to) < 0 && fn(node.key, *node.value.Load())
+	})
+}
+
+// Floor returns the entry with the greatest key less than or equal to key.
+func (s *SkipList[K, V]) Floor(key K) (floor K, value V, ok bool) {
+	var preds, succs [skipListMaxLevel]*skipListNode[K, V]
+
+	for {
+		if found := s.find(key, &preds, &succs); found >= 0 && succs[found].live() {
+			return succs[found].key, *succs[found].value.Load(), true
+		}
+
+		var pred = preds[0]
+		if pred == s.head {
+			return floor, value, false
+		}
+
+		if pred.live() {
+			return pred.key, *pred.value.Load(), true
+		}
+	}
+}
+
+// Ceiling returns the entry with the least key greater than or equal to key.
+func (s *SkipList[K, V]) Ceiling(key K) (ceiling K, value V, ok bool) {
+	var node = s.ceilingNode(key)
+	for node != nil && !node.live() {
+		node = node.next[0].Load()
+	}
+
+	if node == nil {
+		return ceiling, value, false
+	}
+
+	return node.key, *node.value.Load(), true
+}
+
+func (s *SkipList[K, V]) ceilingNode(key K) *skipListNode[K, V] {
+	var preds, succs [skipListMaxLevel]*skipListNode[K, V]
+	s.find(key, &preds, &succs)
+	return succs[0]
+}
+
+func (s *SkipList[K, V]) ascend(node *skipListNode[K, V], do func(node *skipListNode[K, V]) bool) {
+	for ; node != nil; node = node.next[0].Load() {
+		if node.live() && !do(node) {
+			return
+		}
+	}
+}
+
+func (s *SkipList[K, V]) lookup(key K) *skipListNode[K, V] {
+	var preds, succs [skipListMaxLevel]*skipListNode[K, V]
+	if found := s.find(key, &preds, &succs); found >= 0 && succs[found].live() {
+		return succs[found]
+	}
+
+	return nil
+}
+
+// find fills the predecessors and successors of key at every level
+// and returns the highest level the key was found at, or -1.
+func (s *SkipList[K, V]) find(key K, preds, succs *[skipListMaxLevel]*skipListNode[K, V]) int {
+	var (
+		found = -1
+		pred  = s.head
+	)
+
+	for level := skipListMaxLevel - 1; level >= 0; level-- {
+		var curr = pred.next[level].Load()
+		for curr != nil && s.compare(curr.key, key) < 0 {
+			pred, curr = curr, curr.next[level].Load()
+		}
+
+		if found < 0 && curr != nil && s.compare(curr.key, key) == 0 {
+			found = level
+		}
+
+		preds[level], succs[level] = pred, curr
+	}
+
+	return found
+}
+
+// lockPredecessors locks the distinct predecessors of the levels below topLevel and validates them.
+// The returned function unlocks everything that was locked, even if validation failed.
+func (s *SkipList[K, V]) lockPredecessors(topLevel int, preds *[skipListMaxLevel]*skipListNode[K, V], validate func(level int, pred *skipListNode[K, V]) bool) (unlock func(), valid bool) {
+	var locked []*skipListNode[K, V]
+
+	unlock = func() {
+		for _, node := range locked {
+			node.mu.Unlock()
+		}
+	}
+
+	for level := 0; level < topLevel; level++ {
+		var pred = preds[level]
+		if len(locked) == 0 || locked[len(locked)-1] != pred {
+			pred.mu.Lock()
+			locked = append(locked, pred)
+		}
+
+		if !validate(level, pred) {
+			return unlock, false
+		}
+	}
+
+	return unlock, true
+}
+
+func (n *skipListNode[K, V]) live() bool {
+	return n.fullyLinked.Load() && !n.marked.Load()
+}
+
+// randomSkipListLevel returns a level in [1, skipListMaxLevel] with probability 2^-level.
+func randomSkipListLevel() int {
+	return Min(bits.TrailingZeros64(rand.Uint64())+1, skipListMaxLevel)
+}
@@ -0,0 +1,236 @@
+package collection_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func skipListKeys[K any, V any](s *collection.SkipList[K, V]) []K {
+	var keys []K
+	s.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+func TestSkipList(t *testing.T) {
+	s := collection.NewOrderedSkipList[int, string]()
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		if !s.Set(k, "v") {
+			t.Errorf("Set(%v) reported update of a new key", k)
+		}
+	}
+
+	if s.Set(3, "three") {
+		t.Errorf("Set(3) again reported insertion")
+	}
+
+	if v, ok := s.Get(3); !ok || v != "three" {
+		t.Errorf("Get(3) = (%v, %v); want (three, true)", v, ok)
+	}
+
+	if !s.Delete(5) || s.Delete(5) || s.Has(5) {
+		t.Errorf("Delete(5) should succeed exactly once")
+	}
+
+	if got := skipListKeys(s); !slices.Equal(got, []int{1, 3, 7, 9}) || s.Len() != 4 {
+		t.Errorf("Range() = %v, Len() = %v", got, s.Len())
+	}
+}
+
+func TestSkipListQueries(t *testing.T) {
+	s := collection.NewOrderedSkipList[int, int]()
+	for k := 10; k <= 50; k += 10 {
+		s.Set(k, k*k)
+	}
+
+	cases := []struct {
+		name  string
+		query func(int) (int, int, bool)
+		key   int
+		want  int
+		ok    bool
+	}{
+		{name: "floor exact", query: s.Floor, key: 30, want: 30, ok: true},
+		{name: "floor between", query: s.Floor, key: 35, want: 30, ok: true},
+		{name: "floor below", query: s.Floor, key: 5, ok: false},
+		{name: "floor above", query: s.Floor, key: 100, want: 50, ok: true},
+		{name: "ceiling exact", query: s.Ceiling, key: 30, want: 30, ok: true},
+		{name: "ceiling between", query: s.Ceiling, key: 31, want: 40, ok: true},
+		{name: "ceiling above", query: s.Ceiling, key: 51, ok: false},
+		{name: "ceiling below", query: s.Ceiling, key: -1, want: 10, ok: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, value, ok := tc.query(tc.key)
+			if key != tc.want || ok != tc.ok || (ok && value != key*key) {
+				t.Errorf("got (%v, %v, %v); want (%v, %v)", key, value, ok, tc.want, tc.ok)
+			}
+		})
+	}
+
+	var between []int
+	s.Between(15, 50, func(key, _ int) bool {
+		between = append(between, key)
+		return true
+	})
+
+	if !slices.Equal(between, []int{20, 30, 40}) {
+		t.Errorf("Between(15, 50) = %v; want [20 30 40]", between)
+	}
+}
+
+func TestSkipListComparator(t *testing.T) {
+	s := collection.NewSkipList[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	s.Set("b", 1)
+	s.Set("A", 2)
+	s.Set("B", 3)
+
+	if got := skipListKeys(s); !slices.Equal(got, []string{"A", "b"}) {
+		t.Errorf("Range() = %v; want [A b]", got)
+	}
+
+	if v, _ := s.Get("b"); v != 3 {
+		t.Errorf("Get(b) = %v; want 3", v)
+	}
+}
+
+// TestSkipListConcurrent runs writers on disjoint key sets alongside readers,
+// then compares the list with a sorted map built from the same operations.
+func TestSkipListConcurrent(t *testing.T) {
+	const (
+		writers = 8
+		ops     = 2000
+		keys    = 500
+	)
+
+	var (
+		s        = collection.NewOrderedSkipList[int, int]()
+		expected = make([]map[int]int, writers)
+		wg       sync.WaitGroup
+		done     = make(chan struct{})
+	)
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			rnd := rand.New(rand.NewSource(int64(w)))
+			expected[w] = make(map[int]int)
+
+			for i := 0; i < ops; i++ {
+				key := rnd.Intn(keys)*writers + w
+				if rnd.Intn(3) == 0 {
+					s.Delete(key)
+					delete(expected[w], key)
+				} else {
+					s.Set(key, i)
+					expected[w][key] = i
+				}
+			}
+		}(w)
+	}
+
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if got := skipListKeys(s); !sort.IntsAreSorted(got) {
+					t.Errorf("Range() returned unsorted keys")
+					return
+				}
+
+				s.Floor(keys * writers / 2)
+				s.Ceiling(keys * writers / 2)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	var want = make(map[int]int)
+	for _, m := range expected {
+		for k, v := range m {
+			want[k] = v
+		}
+	}
+
+	wantKeys := collection.MapKeys(want)
+	sort.Ints(wantKeys)
+
+	if got := skipListKeys(s); !slices.Equal(got, wantKeys) {
+		t.Fatalf("Range() returned %v keys; want %v", len(got), len(wantKeys))
+	}
+
+	if s.Len() != len(want) {
+		t.Errorf("Len() = %v; want %v", s.Len(), len(want))
+	}
+
+	for k, v := range want {
+		if got, ok := s.Get(k); !ok || got != v {
+			t.Errorf("Get(%v) = (%v, %v); want (%v, true)", k, got, ok, v)
+		}
+	}
+}
+
+// TestSkipListConcurrentSameKeys makes writers race on the same keys,
+// where only the invariants, not the final contents, are deterministic.
+func TestSkipListConcurrentSameKeys(t *testing.T) {
+	var (
+		s  = collection.NewOrderedSkipList[int, int]()
+		wg sync.WaitGroup
+	)
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			rnd := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 2000; i++ {
+				key := rnd.Intn(64)
+				if rnd.Intn(2) == 0 {
+					s.Delete(key)
+				} else {
+					s.Set(key, w)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	got := skipListKeys(s)
+	if !sort.IntsAreSorted(got) || len(slices.Compact(slices.Clone(got))) != len(got) {
+		t.Errorf("Range() = %v; want sorted distinct keys", got)
+	}
+
+	if s.Len() != len(got) {
+		t.Errorf("Len() = %v; want %v", s.Len(), len(got))
+	}
+}
@@ -0,0 +1,383 @@
+package collection
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// Interpolation selects how Percentile estimates a value that falls between two elements.
+type Interpolation int
+
+const (
+	// InterpolationLinear interpolates linearly between the two surrounding elements.
+	InterpolationLinear Interpolation = iota
+	// InterpolationLower takes the lower of the two surrounding elements.
+	InterpolationLower
+	// InterpolationHigher takes the higher of the two surrounding elements.
+	InterpolationHigher
+	// InterpolationNearest takes the element with the nearest rank.
+	InterpolationNearest
+	// InterpolationMidpoint takes the mean of the two surrounding elements.
+	InterpolationMidpoint
+)
+
+// Sum returns the sum of the elements or zero value.
+func Sum[S ~[]T, T Number](source S) T {
+	var result T
+	for _, v := range source {
+		result += v
+	}
+
+	return result
+}
+
+// Mean returns the arithmetic mean of the elements or zero for an empty slice.
+func Mean[S ~[]T, T Number](source S) float64 {
+	if len(source) == 0 {
+		return 0
+	}
+
+	var result float64
+	for _, v := range source {
+		result += float64(v)
+	}
+
+	return result / float64(len(source))
+}
+
+// Median returns the middle value of the elements, the mean of the two middle values
+// for an even length, or zero for an empty slice. The source slice is not modified.
+func Median[S ~[]T, T Number](source S) float64 {
+	return Percentile(source, 50, InterpolationLinear)
+}
+
+// Variance returns the population variance of the elements or zero for an empty slice.
+func Variance[S ~[]T, T Number](source S) float64 {
+	// Welford's algorithm avoids the cancellation of the naive sum of squares.
+	var mean, m2 float64
+	for i, v := range source {
+		var delta = float64(v) - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (float64(v) - mean)
+	}
+
+	if len(source) == 0 {
+		return 0
+	}
+
+	return m2 / float64(len(source))
+}
+
+// StdDev returns the population standard deviation of the elements or zero for an empty slice.
+func StdDev[S ~[]T, T Number](source S) float64 {
+	return math.Sqrt(Variance(source))
+}
+
+// Percentile returns the p-th percentile of the elements, p being clamped to [0, 100],
+// or zero for an empty slice. Values between two elements are estimated using the interpolation.
+// The source slice is not modified.
+func Percentile[S ~[]T, T Number](source S, p float64, interpolation Interpolation) float64 {
+	if len(source) == 0 {
+		return 0
+	}
+
+	var sorted = slices.Clone(source)
+	slices.Sort(sorted)
+
+	var (
+		rank         = Min(Max(p, 0), 100) / 100 * float64(len(sorted)-1)
+		lower, upper = float64(sorted[int(math.Floor(rank))]), float64(sorted[int(math.Ceil(rank))])
+	)
+
+	switch interpolation {
+	case InterpolationLower:
+		return lower
+	case InterpolationHigher:
+		return upper
+	case InterpolationNearest:
+		return float64(sorted[int(math.Round(rank))])
+	case InterpolationMidpoint:
+		return (lower + upper) / 2
+	}
+
+	return lower + (rank-math.Floor(rank))*(upper-lower)
+}
+
+// Mode returns the most frequent elements in order of their first occurrence.
+// It returns several elements if they are equally frequent, and nil for an empty slice.
+func Mode[S ~[]T, T comparable](source S) []T {
+	var (
+		counts = make(map[T]int, len(source))
+		best   int
+		result []T
+	)
+
+	for _, v := range source {
+		counts[v]++
+		best = Max(best, counts[v])
+	}
+
+	for _, v := range source {
+		if counts[v] == best {
+			result = append(result, v)
+			counts[v] = 0
+		}
+	}
+
+	return result
+}
+
+const defaultTDigestCompression = 100
+
+// TDigest is a streaming estimator of quantiles. It keeps a bounded number of weighted
+// centroids, clustered more finely near the tails, so extreme quantiles such as p99 stay accurate.
+// Digests built on different shards can be merged. A TDigest is not safe for concurrent use.
+type TDigest struct {
+	compression float64
+	centroids   []tDigestCentroid
+	buffer      []tDigestCentroid
+	count       float64
+	min, max    float64
+}
+
+type tDigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest returns an empty TDigest. Higher compression keeps more centroids and gives
+// more accurate estimates; a non-positive compression selects the default of 100.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = defaultTDigestCompression
+	}
+
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// TDigestFromChannel adds the values received from the channel to a new TDigest until the channel is closed.
+func TDigestFromChannel[T Number](source <-chan T, compression float64) *TDigest {
+	var result = NewTDigest(compression)
+	for v := range source {
+		result.Add(float64(v))
+	}
+
+	return result
+}
+
+// TDigestFromSeq adds the values yielded by the iterator, e.g. an iter.Seq, to a new TDigest.
+func TDigestFromSeq[T Number](seq func(yield func(T) bool), compression float64) *TDigest {
+	var result = NewTDigest(compression)
+	seq(func(v T) bool {
+		result.Add(float64(v))
+		return true
+	})
+
+	return result
+}
+
+// Add adds the value with a weight of one. NaN values are ignored.
+func (t *TDigest) Add(value float64) {
+	t.AddWeighted(value, 1)
+}
+
+// AddWeighted adds the value with the given weight. NaN values and non-positive weights are ignored.
+func (t *TDigest) AddWeighted(value, weight float64) {
+	if math.IsNaN(value) || !(weight > 0) {
+		return
+	}
+
+	t.buffer = append(t.buffer, tDigestCentroid{mean: value, weight: weight})
+	t.count += weight
+	t.min, t.max = math.Min(t.min, value), math.Max(t.max, value)
+
+	if len(t.buffer) >= int(5*t.compression) {
+		t.compress()
+	}
+}
+
+// Merge adds all values of other to the digest.
+func (t *TDigest) Merge(other *TDigest) {
+	if other.count == 0 {
+		return
+	}
+
+	t.buffer = append(append(t.buffer, other.centroids...), other.buffer...)
+	t.count += other.count
+	t.min, t.max = math.Min(t.min, other.min), math.Max(t.max, other.max)
+	t.compress()
+}
+
+// Count returns the total weight of the added values.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Min returns the smallest added value or zero for an empty digest.
+func (t *TDigest) Min() float64 {
+	if t.count == 0 {
+		return 0
+	}
+
+	return t.min
+}
+
+// Max returns the largest added value or zero for an empty digest.
+func (t *TDigest) Max() float64 {
+	if t.count == 0 {
+		return 0
+	}
+
+	return t.max
+}
+
+// Quantile returns the estimated value below which the q fraction of the values falls,
+// q being clamped to [0, 1], or zero for an empty digest.
+func (t *TDigest) Quantile(q float64) float64 {
+	if t.count == 0 {
+		return 0
+	}
+
+	t.compress()
+
+	var index = Min(Max(q, 0), 1) * t.count
+	if first := t.centroids[0]; index < first.weight/2 {
+		return t.min + index/(first.weight/2)*(first.mean-t.min)
+	}
+
+	var cumulative float64
+	for i := 0; i+1 < len(t.centroids); i++ {
+		var (
+			left, right = t.centroids[i], t.centroids[i+1]
+			leftCenter  = cumulative + left.weight/2
+			rightCenter = cumulative + left.weight + right.weight/2
+		)
+
+		if index < rightCenter {
+			return left.mean + (index-leftCenter)/(rightCenter-leftCenter)*(right.mean-left.mean)
+		}
+
+		cumulative += left.weight
+	}
+
+	var (
+		last       = t.centroids[len(t.centroids)-1]
+		lastCenter = t.count - last.weight/2
+	)
+
+	if index <= lastCenter || last.weight == 0 {
+		return last.mean
+	}
+
+	return last.mean + (index-lastCenter)/(last.weight/2)*(t.max-last.mean)
+}
+
+// Percentile returns the estimated p-th percentile, p being clamped to [0, 100], or zero for an empty digest.
+func (t *TDigest) Percentile(p float64) float64 {
+	return t.Quantile(p / 100)
+}
+
+// MarshalBinary encodes the compression, bounds and centroids of the digest.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+
+	var data = make([]byte, 0, 36+16*len(t.centroids))
+	for _, v := range []float64{t.compression, t.min, t.max} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(t.centroids)))
+	for _, c := range t.centroids {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(c.mean))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(c.weight))
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a digest encoded by MarshalBinary.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 28 {
+		return ErrIncompatibleSketch
+	}
+
+	var (
+		float = func(i int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(data[i:])) }
+		n     = int(binary.LittleEndian.Uint32(data[24:]))
+	)
+
+	if len(data)-28 != 16*n || !(float(0) > 0) {
+		return ErrIncompatibleSketch
+	}
+
+	var (
+		centroids = make([]tDigestCentroid, n)
+		count     float64
+	)
+
+	for i := range centroids {
+		centroids[i] = tDigestCentroid{mean: float(28 + 16*i), weight: float(36 + 16*i)}
+		if !(centroids[i].weight > 0) || i > 0 && centroids[i].mean < centroids[i-1].mean {
+			return ErrIncompatibleSketch
+		}
+
+		count += centroids[i].weight
+	}
+
+	t.compression, t.min, t.max = float(0), float(8), float(16)
+	t.centroids, t.buffer, t.count = centroids, nil, count
+
+	return nil
+}
+
+// compress merges the buffered values into the centroids. Neighbouring centroids are merged
+// as long as the merged centroid spans at most one unit of the k1 scale function,
+// which bounds the size of centroids near the tails more tightly than in the middle.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	var all = append(t.buffer, t.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	var (
+		result = []tDigestCentroid{all[0]}
+		before float64
+		limit  = t.quantileLimit(0)
+	)
+
+	for _, c := range all[1:] {
+		var last = &result[len(result)-1]
+		if (before+last.weight+c.weight)/t.count <= limit {
+			last.weight += c.weight
+			last.mean += (c.mean - last.mean) * c.weight / last.weight
+			continue
+		}
+
+		before += last.weight
+		limit = t.quantileLimit(before / t.count)
+		result = append(result, c)
+	}
+
+	t.centroids, t.buffer = result, t.buffer[:0]
+}
+
+// quantileLimit returns the quantile one unit of the k1 scale function, k(q) = δ/2π·asin(2q-1), above q.
+func (t *TDigest) quantileLimit(q float64) float64 {
+	var k = t.compression/(2*math.Pi)*math.Asin(2*q-1) + 1
+	if k >= t.compression/4 {
+		return 1
+	}
+
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
@@ -0,0 +1,178 @@
+package collection_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestSummaryStatistics(t *testing.T) {
+	source := []int{2, 4, 4, 4, 5, 5, 7, 9}
+
+	if got := collection.Sum(source); got != 40 {
+		t.Errorf("Sum(%v) = %v; want 40", source, got)
+	}
+
+	if got := collection.Mean(source); got != 5 {
+		t.Errorf("Mean(%v) = %v; want 5", source, got)
+	}
+
+	if got := collection.Median(source); got != 4.5 {
+		t.Errorf("Median(%v) = %v; want 4.5", source, got)
+	}
+
+	if got := collection.Variance(source); got != 4 {
+		t.Errorf("Variance(%v) = %v; want 4", source, got)
+	}
+
+	if got := collection.StdDev(source); got != 2 {
+		t.Errorf("StdDev(%v) = %v; want 2", source, got)
+	}
+
+	if got := collection.Mean([]float64{}); got != 0 {
+		t.Errorf("Mean(empty) = %v; want 0", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	source := []int{40, 10, 30, 20}
+
+	cases := []struct {
+		name          string
+		p             float64
+		interpolation collection.Interpolation
+		want          float64
+	}{
+		{name: "linear", p: 50, interpolation: collection.InterpolationLinear, want: 25},
+		{name: "linear p90", p: 90, interpolation: collection.InterpolationLinear, want: 37},
+		{name: "lower", p: 50, interpolation: collection.InterpolationLower, want: 20},
+		{name: "higher", p: 50, interpolation: collection.InterpolationHigher, want: 30},
+		{name: "nearest", p: 40, interpolation: collection.InterpolationNearest, want: 20},
+		{name: "midpoint", p: 40, interpolation: collection.InterpolationMidpoint, want: 25},
+		{name: "clamped", p: 150, interpolation: collection.InterpolationLinear, want: 40},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := collection.Percentile(source, tc.p, tc.interpolation)
+
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("Percentile(%v, %v) = %v; want %v", source, tc.p, got, tc.want)
+			}
+		})
+	}
+
+	if !slices.Equal(source, []int{40, 10, 30, 20}) {
+		t.Errorf("Percentile modified the source: %v", source)
+	}
+}
+
+func TestMode(t *testing.T) {
+	cases := []struct {
+		name   string
+		source []string
+		want   []string
+	}{
+		{name: "single", source: []string{"a", "b", "b", "c"}, want: []string{"b"}},
+		{name: "multimodal", source: []string{"c", "a", "a", "c", "b"}, want: []string{"c", "a"}},
+		{name: "empty", source: nil, want: nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := collection.Mode(tc.source)
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Mode(%v) = %v; want %v", tc.source, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	var (
+		random = rand.New(rand.NewSource(1))
+		values = make([]float64, 100_000)
+		shards = []*collection.TDigest{collection.NewTDigest(0), collection.NewTDigest(0)}
+	)
+
+	for i := range values {
+		values[i] = random.ExpFloat64() * 100
+		shards[i%2].Add(values[i])
+	}
+
+	digest := shards[0]
+	digest.Merge(shards[1])
+
+	if digest.Count() != float64(len(values)) {
+		t.Fatalf("Count() = %v; want %v", digest.Count(), len(values))
+	}
+
+	for _, p := range []float64{0, 1, 50, 95, 99, 99.9, 100} {
+		var (
+			want = collection.Percentile(values, p, collection.InterpolationLinear)
+			got  = digest.Percentile(p)
+		)
+
+		if math.Abs(got-want) > math.Max(want*0.02, 0.5) {
+			t.Errorf("Percentile(%v) = %v; want about %v", p, got, want)
+		}
+	}
+
+	data, err := digest.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	decoded := collection.NewTDigest(0)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	if decoded.Quantile(0.99) != digest.Quantile(0.99) || decoded.Count() != digest.Count() {
+		t.Errorf("decoded Quantile(0.99) = %v; want %v", decoded.Quantile(0.99), digest.Quantile(0.99))
+	}
+}
+
+func TestTDigestFromChannelAndSeq(t *testing.T) {
+	source := make(chan int)
+	go func() {
+		for i := 1; i <= 1000; i++ {
+			source <- i
+		}
+		close(source)
+	}()
+
+	fromChannel := collection.TDigestFromChannel(source, 100)
+
+	fromSeq := collection.TDigestFromSeq(func(yield func(int) bool) {
+		for i := 1; i <= 1000; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}, 100)
+
+	for _, digest := range []*collection.TDigest{fromChannel, fromSeq} {
+		if digest.Min() != 1 || digest.Max() != 1000 || math.Abs(digest.Quantile(0.5)-500.5) > 5 {
+			t.Errorf("Min() = %v, Max() = %v, Quantile(0.5) = %v", digest.Min(), digest.Max(), digest.Quantile(0.5))
+		}
+	}
+
+	if empty := collection.NewTDigest(0); empty.Quantile(0.5) != 0 {
+		t.Errorf("Quantile() of empty digest = %v; want 0", empty.Quantile(0.5))
+	}
+}
+
+// ExamplePercentile: Example function demonstrating the use of the Percentile function.
+func ExamplePercentile() {
+	latencies := []int{12, 15, 11, 90, 14, 13, 16, 250, 12, 14}
+
+	fmt.Println(collection.Percentile(latencies, 50, collection.InterpolationLinear))
+	fmt.Println(collection.Percentile(latencies, 90, collection.InterpolationNearest))
+	// Output:
+	// 14
+	// 90
+}
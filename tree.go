@@ -0,0 +1,217 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tree is a node of a tree holding a value and its children.
+type Tree[T any] struct {
+	Value    T
+	Children []*Tree[T]
+}
+
+// TreeNode is a tree value paired with its depth, as returned by Flatten.
+type TreeNode[T any] struct {
+	Value T
+	Depth int
+}
+
+var (
+	// ErrTreeOrphan is returned by BuildTree for records whose parent is missing.
+	ErrTreeOrphan = errors.New("collection: orphan tree record")
+	// ErrTreeCycle is returned by BuildTree for records whose ancestors form a cycle.
+	ErrTreeCycle = errors.New("collection: tree cycle")
+)
+
+// BuildTree builds a forest from flat records linked by their IDs and parent IDs.
+// Records whose parent ID is the zero value or equal to their own ID are roots;
+// roots and children keep the order of the source slice.
+// Orphans referencing a missing parent, cycles and duplicate IDs are reported in the returned
+// error, wrapping ErrTreeOrphan, ErrTreeCycle and ErrDuplicateKey. Such records are left out
+// together with their descendants, and the forest built from the rest is still returned.
+func BuildTree[S ~[]T, T any, K comparable](source S, idFunc func(T) K, parentFunc func(T) K) ([]*Tree[T], error) {
+	var (
+		zero     K
+		ids      = TransformBy(source, idFunc)
+		parents  = make([]int, len(source))
+		byID     = make(map[K]int, len(source))
+		nodes    = make([]*Tree[T], len(source))
+		children = make(map[int][]int)
+		roots    []*Tree[T]
+		errs     []error
+	)
+
+	for i, v := range source {
+		if _, ok := byID[ids[i]]; ok {
+			errs = append(errs, fmt.Errorf("%w: %v", ErrDuplicateKey, ids[i]))
+			continue
+		}
+
+		byID[ids[i]] = i
+		nodes[i] = &Tree[T]{Value: v}
+	}
+
+	for i, v := range source {
+		parents[i] = -1
+		if nodes[i] == nil {
+			continue
+		}
+
+		var parent = parentFunc(v)
+		if parent == zero || parent == ids[i] {
+			roots = append(roots, nodes[i])
+			continue
+		}
+
+		j, ok := byID[parent]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %v references missing parent %v", ErrTreeOrphan, ids[i], parent))
+			continue
+		}
+
+		parents[i] = j
+		children[j] = append(children[j], i)
+	}
+
+	for i, node := range nodes {
+		if node == nil || len(children[i]) == 0 {
+			continue
+		}
+
+		node.Children = TransformBy(children[i], func(j int) *Tree[T] { return nodes[j] })
+	}
+
+	// Walk up from every record with a parent. Reaching a node visited by the same
+	// walk means a cycle; reaching a node visited by an earlier walk means no new cycle.
+	var walked = make([]int, len(source))
+	for start := range source {
+		if walked[start] != 0 {
+			continue
+		}
+
+		var i = start
+		for i >= 0 && walked[i] == 0 {
+			walked[i] = start + 1
+			i = parents[i]
+		}
+
+		if i < 0 || walked[i] != start+1 {
+			continue
+		}
+
+		var cycle = []K{ids[i]}
+		for j := parents[i]; j != i; j = parents[j] {
+			cycle = append(cycle, ids[j])
+		}
+
+		errs = append(errs, fmt.Errorf("%w: %v", ErrTreeCycle, cycle))
+	}
+
+	return roots, errors.Join(errs...)
+}
+
+// PreOrder visits the nodes of the tree parent first. If visit returns false, the traversal stops.
+func (t *Tree[T]) PreOrder(visit func(node *Tree[T]) bool) {
+	t.preOrder(0, func(node *Tree[T], _ int) bool { return visit(node) })
+}
+
+func (t *Tree[T]) preOrder(depth int, visit func(node *Tree[T], depth int) bool) bool {
+	if !visit(t, depth) {
+		return false
+	}
+
+	for _, child := range t.Children {
+		if !child.preOrder(depth+1, visit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PostOrder visits the nodes of the tree children first. If visit returns false, the traversal stops.
+func (t *Tree[T]) PostOrder(visit func(node *Tree[T]) bool) {
+	t.postOrder(visit)
+}
+
+func (t *Tree[T]) postOrder(visit func(node *Tree[T]) bool) bool {
+	for _, child := range t.Children {
+		if !child.postOrder(visit) {
+			return false
+		}
+	}
+
+	return visit(t)
+}
+
+// LevelOrder visits the nodes of the tree level by level. If visit returns false, the traversal stops.
+func (t *Tree[T]) LevelOrder(visit func(node *Tree[T]) bool) {
+	var queue = []*Tree[T]{t}
+	for len(queue) > 0 {
+		var node = queue[0]
+		queue = queue[1:]
+
+		if !visit(node) {
+			return
+		}
+
+		queue = append(queue, node.Children...)
+	}
+}
+
+// Flatten returns the values of the tree in pre-order together with their depth, the root having depth 0.
+func (t *Tree[T]) Flatten() []TreeNode[T] {
+	var result []TreeNode[T]
+	t.preOrder(0, func(node *Tree[T], depth int) bool {
+		result = append(result, TreeNode[T]{Value: node.Value, Depth: depth})
+		return true
+	})
+
+	return result
+}
+
+// PathTo returns the values from the root down to the first pre-order node matching the predicate.
+// The ok result is false if no node matches.
+func (t *Tree[T]) PathTo(predicate func(T) bool) (path []T, ok bool) {
+	if predicate(t.Value) {
+		return []T{t.Value}, true
+	}
+
+	for _, child := range t.Children {
+		if path, ok := child.PathTo(predicate); ok {
+			return append([]T{t.Value}, path...), true
+		}
+	}
+
+	return nil, false
+}
+
+// Filter returns a copy of the tree with only the nodes that satisfy the filter or have
+// a descendant that does, so matches keep their path to the root. It returns nil if no node matches.
+func (t *Tree[T]) Filter(filter Filter[T]) *Tree[T] {
+	var children []*Tree[T]
+	for _, child := range t.Children {
+		if filtered := child.Filter(filter); filtered != nil {
+			children = append(children, filtered)
+		}
+	}
+
+	if len(children) == 0 && !filter(t.Value) {
+		return nil
+	}
+
+	return &Tree[T]{Value: t.Value, Children: children}
+}
+
+// TreeTransformBy transforms every value of the tree using the provided transform function, keeping its shape.
+func TreeTransformBy[T, K any](source *Tree[T], transform func(T) K) *Tree[K] {
+	var result = &Tree[K]{Value: transform(source.Value)}
+	if len(source.Children) > 0 {
+		result.Children = TransformBy(source.Children, func(child *Tree[T]) *Tree[K] {
+			return TreeTransformBy(child, transform)
+		})
+	}
+
+	return result
+}
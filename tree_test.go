@@ -0,0 +1,168 @@
+package collection_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+type category struct {
+	ID       int
+	ParentID int
+	Name     string
+}
+
+func buildCategories(t *testing.T, rows []category) ([]*collection.Tree[category], error) {
+	t.Helper()
+
+	return collection.BuildTree(rows,
+		func(c category) int { return c.ID },
+		func(c category) int { return c.ParentID },
+	)
+}
+
+func treeNames(nodes []collection.TreeNode[category]) []string {
+	return collection.TransformBy(nodes, func(n collection.TreeNode[category]) string {
+		return strings.Repeat("-", n.Depth) + n.Value.Name
+	})
+}
+
+var categoryRows = []category{
+	{ID: 4, ParentID: 2, Name: "laptops"},
+	{ID: 1, Name: "root"},
+	{ID: 2, ParentID: 1, Name: "computers"},
+	{ID: 3, ParentID: 1, Name: "phones"},
+	{ID: 5, ParentID: 2, Name: "desktops"},
+	{ID: 6, ParentID: 6, Name: "other"},
+}
+
+func TestBuildTree(t *testing.T) {
+	roots, err := buildCategories(t, categoryRows)
+	if err != nil {
+		t.Fatalf("BuildTree() error = %v", err)
+	}
+
+	if len(roots) != 2 {
+		t.Fatalf("BuildTree() returned %d roots; want 2", len(roots))
+	}
+
+	want := []string{"root", "-computers", "--laptops", "--desktops", "-phones"}
+	if got := treeNames(roots[0].Flatten()); !slices.Equal(got, want) {
+		t.Errorf("Flatten() = %v; want %v", got, want)
+	}
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	rows := append(slices.Clone(categoryRows),
+		category{ID: 7, ParentID: 99, Name: "orphan"},
+		category{ID: 8, ParentID: 7, Name: "orphan child"},
+		category{ID: 9, ParentID: 10, Name: "cycle a"},
+		category{ID: 10, ParentID: 9, Name: "cycle b"},
+		category{ID: 11, ParentID: 9, Name: "cycle child"},
+		category{ID: 3, ParentID: 1, Name: "duplicate"},
+	)
+
+	roots, err := buildCategories(t, rows)
+
+	for _, target := range []error{collection.ErrTreeOrphan, collection.ErrTreeCycle, collection.ErrDuplicateKey} {
+		if !errors.Is(err, target) {
+			t.Errorf("BuildTree() error = %v; want %v", err, target)
+		}
+	}
+
+	if got := strings.Count(err.Error(), "\n") + 1; got != 3 {
+		t.Errorf("BuildTree() reported %d errors; want 3:\n%v", got, err)
+	}
+
+	var total int
+	for _, root := range roots {
+		total += len(root.Flatten())
+	}
+
+	if total != len(categoryRows) {
+		t.Errorf("BuildTree() kept %d nodes; want %d", total, len(categoryRows))
+	}
+}
+
+func TestTreeTraversal(t *testing.T) {
+	roots, _ := buildCategories(t, categoryRows)
+	root := roots[0]
+
+	collect := func(traverse func(func(*collection.Tree[category]) bool), limit int) []string {
+		var result []string
+		traverse(func(node *collection.Tree[category]) bool {
+			result = append(result, node.Value.Name)
+			return len(result) < limit
+		})
+
+		return result
+	}
+
+	cases := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "pre order", got: collect(root.PreOrder, 10), want: []string{"root", "computers", "laptops", "desktops", "phones"}},
+		{name: "post order", got: collect(root.PostOrder, 10), want: []string{"laptops", "desktops", "computers", "phones", "root"}},
+		{name: "level order", got: collect(root.LevelOrder, 10), want: []string{"root", "computers", "phones", "laptops", "desktops"}},
+		{name: "post order early exit", got: collect(root.PostOrder, 2), want: []string{"laptops", "desktops"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !slices.Equal(tc.got, tc.want) {
+				t.Errorf("got %v; want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTreePathTo(t *testing.T) {
+	roots, _ := buildCategories(t, categoryRows)
+
+	path, ok := roots[0].PathTo(func(c category) bool { return c.Name == "desktops" })
+	names := collection.TransformBy(path, func(c category) string { return c.Name })
+
+	if !ok || !slices.Equal(names, []string{"root", "computers", "desktops"}) {
+		t.Errorf("PathTo(desktops) = %v, %v", names, ok)
+	}
+
+	if _, ok := roots[0].PathTo(func(c category) bool { return c.Name == "missing" }); ok {
+		t.Errorf("PathTo(missing) found a node")
+	}
+}
+
+func TestTreeFilter(t *testing.T) {
+	roots, _ := buildCategories(t, categoryRows)
+
+	filtered := roots[0].Filter(func(c category) bool { return strings.HasSuffix(c.Name, "tops") })
+
+	want := []string{"root", "-computers", "--laptops", "--desktops"}
+	if got := treeNames(filtered.Flatten()); !slices.Equal(got, want) {
+		t.Errorf("Filter() = %v; want %v", got, want)
+	}
+
+	if roots[0].Filter(func(c category) bool { return false }) != nil {
+		t.Errorf("Filter() without matches should return nil")
+	}
+}
+
+func TestTreeTransformBy(t *testing.T) {
+	roots, _ := buildCategories(t, categoryRows)
+
+	got := collection.TreeTransformBy(roots[0], func(c category) int { return c.ID })
+
+	want := &collection.Tree[int]{Value: 1, Children: []*collection.Tree[int]{
+		{Value: 2, Children: []*collection.Tree[int]{{Value: 4}, {Value: 5}}},
+		{Value: 3},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TreeTransformBy() = %v; want %v", got.Flatten(), want.Flatten())
+	}
+}
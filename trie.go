@@ -0,0 +1,296 @@
+package collection
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SegmentTrie is a prefix tree keyed by sequences of segments, e.g. path elements of routes.
+// It is stored as a radix tree: chains of nodes without values are compressed into a single edge.
+// Iteration visits keys in lexicographic order of their segments.
+type SegmentTrie[K constraints.Ordered, V any] struct {
+	root radixNode[K, V]
+	size int
+}
+
+type radixNode[K constraints.Ordered, V any] struct {
+	prefix   []K
+	value    V
+	hasValue bool
+	children []*radixNode[K, V]
+}
+
+// NewSegmentTrie returns an empty SegmentTrie.
+func NewSegmentTrie[K constraints.Ordered, V any]() *SegmentTrie[K, V] {
+	return &SegmentTrie[K, V]{}
+}
+
+// Len returns the number of keys.
+func (t *SegmentTrie[K, V]) Len() int {
+	return t.size
+}
+
+// Insert stores the value for the key and reports whether it replaced an existing value.
+func (t *SegmentTrie[K, V]) Insert(key []K, value V) (replaced bool) {
+	var n = &t.root
+
+	for len(key) > 0 {
+		var i, child = n.child(key[0])
+		if child == nil {
+			var leaf = &radixNode[K, V]{prefix: Clone(key), value: value, hasValue: true}
+
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = leaf
+
+			t.size++
+			return false
+		}
+
+		var common = commonPrefixLen(child.prefix, key)
+		if common < len(child.prefix) {
+			var split = &radixNode[K, V]{prefix: child.prefix[:common:common], children: []*radixNode[K, V]{child}}
+			child.prefix = child.prefix[common:]
+			n.children[i] = split
+			child = split
+		}
+
+		n, key = child, key[common:]
+	}
+
+	replaced = n.hasValue
+	n.value, n.hasValue = value, true
+
+	if !replaced {
+		t.size++
+	}
+
+	return replaced
+}
+
+// Get returns the value stored for the key.
+func (t *SegmentTrie[K, V]) Get(key []K) (value V, ok bool) {
+	var n = t.root.find(key)
+	if n == nil || !n.hasValue {
+		return value, false
+	}
+
+	return n.value, true
+}
+
+// Delete removes the key and returns its value. The ok result reports whether the key was present.
+func (t *SegmentTrie[K, V]) Delete(key []K) (value V, ok bool) {
+	value, ok = t.root.remove(key)
+	if ok {
+		t.size--
+	}
+
+	return value, ok
+}
+
+// LongestPrefixMatch returns the longest stored key that is a prefix of key, and its value.
+// The ok result is false if no stored key is a prefix of key.
+func (t *SegmentTrie[K, V]) LongestPrefixMatch(key []K) (prefix []K, value V, ok bool) {
+	var (
+		n        = &t.root
+		consumed int
+	)
+
+	for {
+		if n.hasValue {
+			prefix, value, ok = key[:consumed:consumed], n.value, true
+		}
+
+		if consumed == len(key) {
+			return prefix, value, ok
+		}
+
+		var _, child = n.child(key[consumed])
+		if child == nil || !hasPrefix(key[consumed:], child.prefix) {
+			return prefix, value, ok
+		}
+
+		n, consumed = child, consumed+len(child.prefix)
+	}
+}
+
+// WalkPrefix calls fn for each key starting with prefix in lexicographic order.
+// If fn returns false, the walk stops.
+func (t *SegmentTrie[K, V]) WalkPrefix(prefix []K, fn func(key []K, value V) bool) {
+	var (
+		n   = &t.root
+		key []K
+	)
+
+	for len(prefix) > 0 {
+		var _, child = n.child(prefix[0])
+		if child == nil {
+			return
+		}
+
+		switch {
+		case hasPrefix(child.prefix, prefix):
+			prefix = nil
+		case hasPrefix(prefix, child.prefix):
+			prefix = prefix[len(child.prefix):]
+		default:
+			return
+		}
+
+		n, key = child, append(key, child.prefix...)
+	}
+
+	n.walk(key, fn)
+}
+
+// Walk calls fn for each key in lexicographic order. If fn returns false, the walk stops.
+func (t *SegmentTrie[K, V]) Walk(fn func(key []K, value V) bool) {
+	t.root.walk(nil, fn)
+}
+
+func (n *radixNode[K, V]) child(segment K) (int, *radixNode[K, V]) {
+	var i = sort.Search(len(n.children), func(i int) bool { return n.children[i].prefix[0] >= segment })
+	if i < len(n.children) && n.children[i].prefix[0] == segment {
+		return i, n.children[i]
+	}
+
+	return i, nil
+}
+
+func (n *radixNode[K, V]) find(key []K) *radixNode[K, V] {
+	for len(key) > 0 {
+		var _, child = n.child(key[0])
+		if child == nil || !hasPrefix(key, child.prefix) {
+			return nil
+		}
+
+		n, key = child, key[len(child.prefix):]
+	}
+
+	return n
+}
+
+func (n *radixNode[K, V]) remove(key []K) (value V, ok bool) {
+	if len(key) == 0 {
+		if !n.hasValue {
+			return value, false
+		}
+
+		value = n.value
+		n.value, n.hasValue = *new(V), false
+
+		return value, true
+	}
+
+	var i, child = n.child(key[0])
+	if child == nil || !hasPrefix(key, child.prefix) {
+		return value, false
+	}
+
+	if value, ok = child.remove(key[len(child.prefix):]); !ok {
+		return value, false
+	}
+
+	if !child.hasValue {
+		switch len(child.children) {
+		case 0:
+			n.children = append(n.children[:i], n.children[i+1:]...)
+		case 1:
+			var grandchild = child.children[0]
+			grandchild.prefix = append(Clone(child.prefix), grandchild.prefix...)
+			n.children[i] = grandchild
+		}
+	}
+
+	return value, true
+}
+
+func (n *radixNode[K, V]) walk(key []K, fn func(key []K, value V) bool) bool {
+	if n.hasValue && !fn(Clone(key), n.value) {
+		return false
+	}
+
+	for _, child := range n.children {
+		if !child.walk(append(key[:len(key):len(key)], child.prefix...), fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func commonPrefixLen[K comparable](l, r []K) int {
+	var i int
+	for i < len(l) && i < len(r) && l[i] == r[i] {
+		i++
+	}
+
+	return i
+}
+
+func hasPrefix[K comparable](s, prefix []K) bool {
+	return len(s) >= len(prefix) && Equal(s[:len(prefix)], prefix)
+}
+
+// Trie is a prefix tree keyed by strings, e.g. for route tables and autocomplete.
+// Keys are compared byte-wise, so iteration follows the lexicographic order of strings.
+type Trie[V any] struct {
+	t SegmentTrie[byte, V]
+}
+
+// NewTrie returns an empty Trie.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// Len returns the number of keys.
+func (t *Trie[V]) Len() int {
+	return t.t.Len()
+}
+
+// Insert stores the value for the key and reports whether it replaced an existing value.
+func (t *Trie[V]) Insert(key string, value V) (replaced bool) {
+	return t.t.Insert([]byte(key), value)
+}
+
+// Get returns the value stored for the key.
+func (t *Trie[V]) Get(key string) (value V, ok bool) {
+	return t.t.Get([]byte(key))
+}
+
+// Delete removes the key and returns its value. The ok result reports whether the key was present.
+func (t *Trie[V]) Delete(key string) (value V, ok bool) {
+	return t.t.Delete([]byte(key))
+}
+
+// LongestPrefixMatch returns the longest stored key that is a prefix of key, and its value.
+// The ok result is false if no stored key is a prefix of key.
+func (t *Trie[V]) LongestPrefixMatch(key string) (prefix string, value V, ok bool) {
+	p, value, ok := t.t.LongestPrefixMatch([]byte(key))
+	return string(p), value, ok
+}
+
+// WalkPrefix calls fn for each key starting with prefix in lexicographic order.
+// If fn returns false, the walk stops.
+func (t *Trie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	t.t.WalkPrefix([]byte(prefix), func(key []byte, value V) bool {
+		return fn(string(key), value)
+	})
+}
+
+// Walk calls fn for each key in lexicographic order. If fn returns false, the walk stops.
+func (t *Trie[V]) Walk(fn func(key string, value V) bool) {
+	t.WalkPrefix("", fn)
+}
+
+// Keys returns all keys in lexicographic order.
+func (t *Trie[V]) Keys() []string {
+	var result = make([]string, 0, t.Len())
+	t.Walk(func(key string, _ V) bool {
+		result = append(result, key)
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,195 @@
+package collection_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sergeydobrodey/collection"
+)
+
+func TestTrie(t *testing.T) {
+	trie := collection.NewTrie[int]()
+	words := []string{"team", "test", "toast", "te", "tea", "", "tester"}
+
+	for i, w := range words {
+		if trie.Insert(w, i) {
+			t.Errorf("Insert(%q) replaced a value", w)
+		}
+	}
+
+	if !trie.Insert("tea", 100) {
+		t.Errorf("Insert(tea) again should replace")
+	}
+
+	if trie.Len() != len(words) {
+		t.Errorf("Len() = %v; want %v", trie.Len(), len(words))
+	}
+
+	if v, ok := trie.Get("tea"); !ok || v != 100 {
+		t.Errorf("Get(tea) = (%v, %v); want (100, true)", v, ok)
+	}
+
+	if _, ok := trie.Get("tes"); ok {
+		t.Errorf("Get(tes) found an intermediate node")
+	}
+
+	want := []string{"", "te", "tea", "team", "test", "tester", "toast"}
+	if got := trie.Keys(); !slices.Equal(got, want) {
+		t.Errorf("Keys() = %q; want %q", got, want)
+	}
+}
+
+func TestTrieDelete(t *testing.T) {
+	trie := collection.NewTrie[int]()
+	for i, w := range []string{"romane", "romanus", "romulus", "rubens", "ruber"} {
+		trie.Insert(w, i)
+	}
+
+	if _, ok := trie.Delete("roman"); ok {
+		t.Errorf("Delete(roman) removed a missing key")
+	}
+
+	if v, ok := trie.Delete("romanus"); !ok || v != 1 {
+		t.Errorf("Delete(romanus) = (%v, %v); want (1, true)", v, ok)
+	}
+
+	trie.Delete("romulus")
+	trie.Delete("rubens")
+
+	if got := trie.Keys(); !slices.Equal(got, []string{"romane", "ruber"}) {
+		t.Errorf("Keys() = %q", got)
+	}
+
+	for _, w := range []string{"romane", "ruber"} {
+		if _, ok := trie.Get(w); !ok {
+			t.Errorf("Get(%q) lost after compaction", w)
+		}
+	}
+}
+
+func TestTrieLongestPrefixMatch(t *testing.T) {
+	trie := collection.NewTrie[string]()
+	trie.Insert("/", "root")
+	trie.Insert("/api/", "api")
+	trie.Insert("/api/v1/users", "users")
+
+	cases := []struct {
+		key    string
+		prefix string
+		value  string
+		ok     bool
+	}{
+		{key: "/api/v1/users/42", prefix: "/api/v1/users", value: "users", ok: true},
+		{key: "/api/v1/orders", prefix: "/api/", value: "api", ok: true},
+		{key: "/static/app.js", prefix: "/", value: "root", ok: true},
+		{key: "api", ok: false},
+	}
+
+	for _, tc := range cases {
+		prefix, value, ok := trie.LongestPrefixMatch(tc.key)
+		if prefix != tc.prefix || value != tc.value || ok != tc.ok {
+			t.Errorf("LongestPrefixMatch(%q) = (%q, %q, %v); want (%q, %q, %v)", tc.key, prefix, value, ok, tc.prefix, tc.value, tc.ok)
+		}
+	}
+}
+
+func TestTrieWalkPrefix(t *testing.T) {
+	trie := collection.NewTrie[int]()
+	for i, w := range []string{"go", "gopher", "golang", "google", "rust", "gone"} {
+		trie.Insert(w, i)
+	}
+
+	walk := func(prefix string, limit int) []string {
+		var result []string
+		trie.WalkPrefix(prefix, func(key string, _ int) bool {
+			result = append(result, key)
+			return len(result) < limit
+		})
+
+		return result
+	}
+
+	cases := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{prefix: "go", limit: 10, want: []string{"go", "golang", "gone", "google", "gopher"}},
+		{prefix: "gol", limit: 10, want: []string{"golang"}},
+		{prefix: "goo", limit: 10, want: []string{"google"}},
+		{prefix: "gox", limit: 10, want: nil},
+		{prefix: "", limit: 2, want: []string{"go", "golang"}},
+	}
+
+	for _, tc := range cases {
+		if got := walk(tc.prefix, tc.limit); !slices.Equal(got, tc.want) {
+			t.Errorf("WalkPrefix(%q) = %q; want %q", tc.prefix, got, tc.want)
+		}
+	}
+}
+
+func TestSegmentTrie(t *testing.T) {
+	routes := collection.NewSegmentTrie[string, string]()
+	routes.Insert([]string{"api", "v1"}, "v1")
+	routes.Insert([]string{"api", "v1", "users"}, "users")
+	routes.Insert([]string{"api", "v2"}, "v2")
+
+	prefix, value, ok := routes.LongestPrefixMatch(strings.Split("api/v1/users/42", "/"))
+	if !ok || value != "users" || !slices.Equal(prefix, []string{"api", "v1", "users"}) {
+		t.Errorf("LongestPrefixMatch() = (%v, %v, %v)", prefix, value, ok)
+	}
+
+	var keys []string
+	routes.WalkPrefix([]string{"api"}, func(key []string, _ string) bool {
+		keys = append(keys, strings.Join(key, "/"))
+		return true
+	})
+
+	if want := []string{"api/v1", "api/v1/users", "api/v2"}; !slices.Equal(keys, want) {
+		t.Errorf("WalkPrefix(api) = %v; want %v", keys, want)
+	}
+}
+
+func TestTrieMatchesMap(t *testing.T) {
+	var (
+		rnd      = rand.New(rand.NewSource(1))
+		trie     = collection.NewTrie[int]()
+		expected = make(map[string]int)
+	)
+
+	for i := 0; i < 5000; i++ {
+		var b = make([]byte, rnd.Intn(6))
+		for j := range b {
+			b[j] = "abc"[rnd.Intn(3)]
+		}
+
+		key := string(b)
+		if rnd.Intn(3) == 0 {
+			_, ok := trie.Delete(key)
+			_, want := expected[key]
+			if ok != want {
+				t.Fatalf("Delete(%q) = %v; want %v", key, ok, want)
+			}
+			delete(expected, key)
+		} else {
+			trie.Insert(key, i)
+			expected[key] = i
+		}
+	}
+
+	keys := collection.MapKeys(expected)
+	sort.Strings(keys)
+
+	if got := trie.Keys(); !slices.Equal(got, keys) {
+		t.Fatalf("Keys() = %q; want %q", got, keys)
+	}
+
+	for key, want := range expected {
+		if got, ok := trie.Get(key); !ok || got != want {
+			t.Errorf("Get(%q) = (%v, %v); want (%v, true)", key, got, ok, want)
+		}
+	}
+}